@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"github.com/ilyakaznacheev/cleanenv"
+	"github.com/menyasosali/mts/config"
+	"github.com/menyasosali/mts/internal/service/db"
+	"github.com/menyasosali/mts/internal/service/gc"
+	"github.com/menyasosali/mts/internal/service/minio"
+	"github.com/menyasosali/mts/pkg/logger"
+	"github.com/menyasosali/mts/pkg/postgres"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// gc делает один проход сборки мусора: удаляет объекты MinIO без ссылок из базы и заново ставит
+// в очередь или помечает failed зависшие изображения. С -dry-run только печатает, что было бы сделано
+func main() {
+	cfgPath := flag.String("config", "./config/config.yaml", "path to config file")
+	dryRun := flag.Bool("dry-run", false, "only report orphaned objects and stuck images, do not change them")
+	flag.Parse()
+
+	cfg := &config.GateConfig{}
+	err := cleanenv.ReadConfig(*cfgPath, cfg)
+	if err != nil {
+		log.Fatalf("Failed to read config file: %v", err)
+	}
+	cfg.GC.DryRun = cfg.GC.DryRun || *dryRun
+
+	l := logger.NewLogger(cfg.Log.Level)
+
+	pg, err := postgres.New(cfg.Postgres.URL, postgres.MaxPoolSize(cfg.Postgres.PoolMax))
+	if err != nil {
+		l.Fatal(fmt.Errorf("gc - main.go - postgres.New: %w", err))
+	}
+	defer pg.Close()
+
+	minioClient, err := minio.NewMinioClient(l, cfg.Minio)
+	if err != nil {
+		log.Fatal("Failed to create MinIO client:", err)
+	}
+
+	collector := gc.NewCollector(l, minioClient, db.NewStore(l, pg), cfg.Kafka.Topic, cfg.GC)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	report, err := collector.Collect(ctx)
+	if err != nil {
+		l.Error(fmt.Errorf("gc - main.go - Collect: %w", err))
+	}
+
+	fmt.Println(report)
+}
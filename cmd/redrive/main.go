@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"github.com/ilyakaznacheev/cleanenv"
+	"github.com/menyasosali/mts/config"
+	"github.com/menyasosali/mts/internal/service/kafka"
+	"github.com/menyasosali/mts/pkg/logger"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// redrive переотправляет сообщения из dead-letter topic (с -quarantine - из карантина, после
+// обновления worker'а до новой версии схемы задач) в основной topic worker'а
+func main() {
+	cfgPath := flag.String("config", "./config/config.yaml", "path to config file")
+	dryRun := flag.Bool("dry-run", false, "only print dead letters, do not redrive or commit them")
+	quarantine := flag.Bool("quarantine", false, "redrive the quarantine topic instead of the dead-letter topic")
+	flag.Parse()
+
+	cfg := &config.WorkerConfig{}
+	err := cleanenv.ReadConfig(*cfgPath, cfg)
+	if err != nil {
+		log.Fatalf("Failed to read config file: %v", err)
+	}
+
+	l := logger.NewLogger(cfg.Log.Level)
+
+	producer, err := kafka.NewImageProducer(l, cfg.Kafka)
+	if err != nil {
+		log.Fatal("Failed to create Kafka producer:", err)
+	}
+	defer producer.Close()
+
+	source := cfg.Kafka.DeadLetterTopic
+	if *quarantine {
+		source = cfg.Kafka.QuarantineTopic
+	}
+
+	redriver, err := kafka.NewRedriver(l, producer, cfg.Kafka, source, *dryRun)
+	if err != nil {
+		log.Fatal("Failed to create redriver:", err)
+	}
+	defer redriver.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	redriven, err := redriver.Redrive(ctx)
+	if err != nil {
+		l.Error(fmt.Errorf("redrive - main.go - Redrive: %w", err))
+	}
+
+	fmt.Printf("Redriven %d messages from %s to %s (dry run: %t)\n",
+		redriven, source, cfg.Kafka.Topic, *dryRun)
+}
@@ -1,17 +1,26 @@
 package config
 
+import "time"
+
 type GateConfig struct {
 	Log      LogConfig      `yaml:"logger"`
 	Postgres PostgresConfig `yaml:"postgres"`
 	Minio    MinioConfig    `yaml:"minio"`
 	Kafka    KafkaConfig    `yaml:"kafka"`
 	HTTP     HTTPConfig     `yaml:"http"`
+	Render   RenderConfig   `yaml:"render"`
+	Upload   UploadConfig   `yaml:"upload"`
+	Outbox   OutboxConfig   `yaml:"outbox"`
+	GC       GCConfig       `yaml:"gc"`
+	Webhook  WebhookConfig  `yaml:"webhook"`
 }
 
 type WorkerConfig struct {
-	Log   LogConfig   `yaml:"logger"`
-	Kafka KafkaConfig `yaml:"kafka"`
-	Minio MinioConfig `yaml:"minio"`
+	Log      LogConfig      `yaml:"logger"`
+	Postgres PostgresConfig `yaml:"postgres"`
+	Kafka    KafkaConfig    `yaml:"kafka"`
+	Minio    MinioConfig    `yaml:"minio"`
+	Presets  []PresetConfig `yaml:"presets"`
 }
 
 type PostgresConfig struct {
@@ -31,11 +40,94 @@ type LogConfig struct {
 }
 
 type KafkaConfig struct {
-	Brokers []string `env-required:"true" yaml:"brokers" env:"KAFKA_BROKERS" env-default:"kafka:9092"`
-	Topic   string   `env-required:"true" yaml:"topic" env:"KAFKA_TOPIC" env-default:"config-topic"`
-	GroupID string   `env-required:"true" yaml:"group_id" env:"KAFKA_GROUP_ID" env-default:"worker-group"`
+	Brokers         []string      `env-required:"true" yaml:"brokers" env:"KAFKA_BROKERS" env-default:"kafka:9092"`
+	Topic           string        `env-required:"true" yaml:"topic" env:"KAFKA_TOPIC" env-default:"config-topic"`
+	GroupID         string        `env-required:"true" yaml:"group_id" env:"KAFKA_GROUP_ID" env-default:"worker-group"`
+	DeadLetterTopic string        `yaml:"dead_letter_topic" env:"KAFKA_DEAD_LETTER_TOPIC" env-default:"config-topic-dlq"`
+	QuarantineTopic string        `yaml:"quarantine_topic" env:"KAFKA_QUARANTINE_TOPIC" env-default:"config-topic-quarantine"`
+	MaxAttempts     int           `yaml:"max_attempts" env:"KAFKA_MAX_ATTEMPTS" env-default:"3"`
+	RetryBackoff    time.Duration `yaml:"retry_backoff" env:"KAFKA_RETRY_BACKOFF" env-default:"1s"`
+	MaxRetryBackoff time.Duration `yaml:"max_retry_backoff" env:"KAFKA_MAX_RETRY_BACKOFF" env-default:"30s"`
 }
 
+// PresetConfig - размер превью. Нулевая ширина или высота считается по пропорциям оригинала.
+// Fit: contain, cover, fill (добивает фоном Background) или exact. Crop для cover: center или smart.
+// Formats: original, jpeg, png, gif, webp, avif - каждый формат сохраняется отдельным объектом
+type PresetConfig struct {
+	Name           string   `yaml:"name"`
+	Width          uint     `yaml:"width"`
+	Height         uint     `yaml:"height"`
+	Fit            string   `yaml:"fit"`
+	Crop           string   `yaml:"crop"`
+	Background     string   `yaml:"background"`
+	NoUpscale      bool     `yaml:"no_upscale"`
+	Formats        []string `yaml:"formats"`
+	Quality        int      `yaml:"quality"`
+	PNGCompression string   `yaml:"png_compression"`
+}
+
+// HTTPConfig - ReadTimeout и WriteTimeout действуют только на HTTP-порт, gRPC (GRPCPort) слушается
+// отдельно без них: потоки WatchImage и UploadImage живут дольше
 type HTTPConfig struct {
-	Port string `env-required:"true" yaml:"port" env:"HTTP_PORT" env-default:"8080"`
+	Port         string        `env-required:"true" yaml:"port" env:"HTTP_PORT" env-default:"8080"`
+	GRPCPort     string        `yaml:"grpc_port" env:"GRPC_PORT" env-default:"9090"`
+	ReadTimeout  time.Duration `yaml:"read_timeout" env:"HTTP_READ_TIMEOUT" env-default:"5s"`
+	WriteTimeout time.Duration `yaml:"write_timeout" env:"HTTP_WRITE_TIMEOUT" env-default:"5s"`
+}
+
+// OutboxConfig - как часто relay ищет неотправленные сообщения, сколько берет за раз,
+// задержка повтора после ошибки Kafka и сколько хранятся отправленные сообщения
+type OutboxConfig struct {
+	PollInterval    time.Duration `yaml:"poll_interval" env:"OUTBOX_POLL_INTERVAL" env-default:"1s"`
+	BatchSize       int           `yaml:"batch_size" env:"OUTBOX_BATCH_SIZE" env-default:"100"`
+	RetryBackoff    time.Duration `yaml:"retry_backoff" env:"OUTBOX_RETRY_BACKOFF" env-default:"1s"`
+	MaxRetryBackoff time.Duration `yaml:"max_retry_backoff" env:"OUTBOX_MAX_RETRY_BACKOFF" env-default:"1m"`
+	Retention       time.Duration `yaml:"retention" env:"OUTBOX_RETENTION" env-default:"24h"`
+}
+
+// GCConfig - сборка мусора. Interval - период запуска в gateway (0 - только через cmd/gc).
+// Объекты MinIO без ссылок из базы удаляются, если они старше GracePeriod. Изображения, которые
+// дольше StuckAfter не выходят из очереди, заново ставятся в очередь, пока с загрузки прошло
+// меньше RequeueWindow, иначе или без оригинала помечаются failed. DryRun только считает
+type GCConfig struct {
+	Interval      time.Duration `yaml:"interval" env:"GC_INTERVAL" env-default:"1h"`
+	GracePeriod   time.Duration `yaml:"grace_period" env:"GC_GRACE_PERIOD" env-default:"24h"`
+	StuckAfter    time.Duration `yaml:"stuck_after" env:"GC_STUCK_AFTER" env-default:"1h"`
+	RequeueWindow time.Duration `yaml:"requeue_window" env:"GC_REQUEUE_WINDOW" env-default:"24h"`
+	BatchSize     int           `yaml:"batch_size" env:"GC_BATCH_SIZE" env-default:"1000"`
+	DryRun        bool          `yaml:"dry_run" env:"GC_DRY_RUN" env-default:"false"`
+}
+
+// WebhookConfig - уведомления о завершении обработки. Тело подписывается HMAC-SHA256 с SigningSecret.
+// После неудачной попытки N следующая делается через RetrySchedule[N-1], когда расписание
+// закончилось - доставка считается неудачной. Clients - callback_url зарегистрированных клиентов
+// по тенанту (x-tenant-id), если загрузка не указала свой. Во внутреннюю сеть (loopback, private,
+// link-local) webhooks не ходят, кроме сетей из AllowedNetworks в формате CIDR
+type WebhookConfig struct {
+	SigningSecret   string                `env-required:"true" yaml:"signing_secret" env:"WEBHOOK_SIGNING_SECRET"`
+	Timeout         time.Duration         `yaml:"timeout" env:"WEBHOOK_TIMEOUT" env-default:"10s"`
+	PollInterval    time.Duration         `yaml:"poll_interval" env:"WEBHOOK_POLL_INTERVAL" env-default:"1s"`
+	BatchSize       int                   `yaml:"batch_size" env:"WEBHOOK_BATCH_SIZE" env-default:"10"`
+	RetrySchedule   []time.Duration       `yaml:"retry_schedule" env:"WEBHOOK_RETRY_SCHEDULE" env-default:"10s,1m,5m,30m,2h,6h"`
+	Clients         []WebhookClientConfig `yaml:"clients"`
+	AllowedNetworks []string              `yaml:"allowed_networks" env:"WEBHOOK_ALLOWED_NETWORKS"`
+}
+
+type WebhookClientConfig struct {
+	Tenant      string `yaml:"tenant"`
+	CallbackURL string `yaml:"callback_url"`
+}
+
+// UploadConfig - MaxSize ограничивает размер загружаемого файла в байтах, PresignExpiry - срок
+// действия url для прямой загрузки в MinIO
+type UploadConfig struct {
+	MaxSize       int64         `yaml:"max_size" env:"UPLOAD_MAX_SIZE" env-default:"104857600"`
+	PresignExpiry time.Duration `yaml:"presign_expiry" env:"UPLOAD_PRESIGN_EXPIRY" env-default:"15m"`
+}
+
+// RenderConfig - ресайз на лету, параметры запроса подписываются HMAC-SHA256 с SigningSecret
+type RenderConfig struct {
+	SigningSecret string `env-required:"true" yaml:"signing_secret" env:"RENDER_SIGNING_SECRET"`
+	MaxWidth      uint   `yaml:"max_width" env:"RENDER_MAX_WIDTH" env-default:"4096"`
+	MaxHeight     uint   `yaml:"max_height" env:"RENDER_MAX_HEIGHT" env-default:"4096"`
 }
@@ -0,0 +1,9 @@
+package domain
+
+// ImageEvent - смена статуса изображения или готовое превью (тогда задан Variant)
+type ImageEvent struct {
+	ImageID       string
+	Status        ImageStatus
+	FailureReason string
+	Variant       *Variant
+}
@@ -1,10 +1,63 @@
 package domain
 
+import "time"
+
+type ImageStatus string
+
+const (
+	StatusUploaded   ImageStatus = "uploaded"
+	StatusQueued     ImageStatus = "queued"
+	StatusProcessing ImageStatus = "processing"
+	StatusReady      ImageStatus = "ready"
+	StatusFailed     ImageStatus = "failed"
+	// StatusDeleted - надгробие: изображение удалено, но объекты в MinIO и строка еще не убраны.
+	// Ставится только через Store.DeleteImage, в imageTransitions его нет
+	StatusDeleted ImageStatus = "deleted"
+)
+
+// imageTransitions - из каких статусов можно перейти в данный
+var imageTransitions = map[ImageStatus][]ImageStatus{
+	StatusUploaded:   {},
+	StatusQueued:     {StatusUploaded, StatusReady, StatusFailed},
+	StatusProcessing: {StatusQueued, StatusProcessing, StatusFailed},
+	StatusReady:      {StatusProcessing},
+	StatusFailed:     {StatusUploaded, StatusQueued, StatusProcessing},
+}
+
+// PreviousStatuses returns the statuses an image may be in before moving to status.
+func PreviousStatuses(status ImageStatus) []ImageStatus {
+	return imageTransitions[status]
+}
+
+// Variant - превью изображения по одному пресету из конфига worker'а
+type Variant struct {
+	Preset      string
+	Format      string
+	ContentType string
+	Key         string // ключ объекта в MinIO
+	URL         string
+	Width       int
+	Height      int
+}
+
+// FocalPoint - центр обрезки в долях от ширины и высоты оригинала, 0..1
+type FocalPoint struct {
+	X float64
+	Y float64
+}
+
 type ImgDescriptor struct {
-	ID     string //uuid
-	Name   string
-	URL    string
-	URL512 string
-	URL256 string
-	URL16  string
+	ID            string //uuid
+	Name          string
+	URL           string
+	ObjectKey     string // ключ оригинала в MinIO
+	SHA256        string // hex sha256 оригинала, по нему одинаковые файлы не загружаются повторно
+	CallbackURL   string // сюда POST'ится webhook по завершении обработки
+	Variants      []Variant
+	FocalPoint    *FocalPoint
+	Metadata      *ImageMetadata
+	Status        ImageStatus
+	FailureReason string
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
 }
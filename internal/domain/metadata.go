@@ -0,0 +1,29 @@
+package domain
+
+import "time"
+
+// ImageMetadata - свойства оригинала, которые worker считывает при обработке. Хранится в
+// images.metadata (JSONB), до первой обработки его нет. Width и Height - размер в пикселях без
+// учета EXIF Orientation, как у превью
+type ImageMetadata struct {
+	Width      int    `json:"width"`
+	Height     int    `json:"height"`
+	Format     string `json:"format"`     // jpeg, png, webp, ... по содержимому файла
+	Size       int64  `json:"size"`       // байт
+	ColorModel string `json:"colorModel"` // ycbcr, rgba, gray, cmyk, paletted, ...
+	EXIF       *EXIF  `json:"exif,omitempty"`
+}
+
+// EXIF - выбранные поля EXIF оригинала, пустые поля в файле не заданы
+type EXIF struct {
+	CameraMake  string     `json:"cameraMake,omitempty"`
+	CameraModel string     `json:"cameraModel,omitempty"`
+	CapturedAt  *time.Time `json:"capturedAt,omitempty"`
+	Orientation int        `json:"orientation,omitempty"` // 1..8
+	GPS         *GPS       `json:"gps,omitempty"`
+}
+
+type GPS struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
@@ -0,0 +1,27 @@
+package domain
+
+import "time"
+
+// Upload - состояние возобновляемой (tus) загрузки оригинала. Байты, которых пока не хватает
+// на часть multipart-загрузки MinIO, хранятся в Tail и входят в Offset
+type Upload struct {
+	ID          string
+	Key         string // ключ объекта в MinIO
+	MultipartID string // id multipart-загрузки в MinIO
+	Filename    string
+	ContentType string
+	Metadata    string // Upload-Metadata как его прислал клиент
+	Length      int64
+	Offset      int64
+	PartCount   int
+	Tail        []byte
+	HashState   []byte // состояние sha256 по принятым байтам (encoding.BinaryMarshaler)
+	OriginalKey string // ключ оригинала после сборки и переноса в originals/, до этого пустой
+	ImageID     string // заполняется после завершения загрузки
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (u *Upload) Completed() bool {
+	return u.ImageID != ""
+}
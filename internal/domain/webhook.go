@@ -0,0 +1,37 @@
+package domain
+
+import "time"
+
+type WebhookStatus string
+
+const (
+	WebhookPending   WebhookStatus = "pending"
+	WebhookDelivered WebhookStatus = "delivered"
+	WebhookFailed    WebhookStatus = "failed" // попытки по расписанию закончились
+)
+
+// WebhookDelivery - доставка события image.ready или image.failed на callback_url изображения
+type WebhookDelivery struct {
+	ID             int64
+	ImageID        string
+	URL            string
+	Event          string
+	Payload        []byte // nil до первой попытки
+	Status         WebhookStatus
+	Attempts       int
+	LastStatusCode int
+	LastError      string
+	NextAttemptAt  time.Time
+	CreatedAt      time.Time
+	DeliveredAt    *time.Time
+	AttemptLog     []WebhookAttempt
+}
+
+// WebhookAttempt - одна попытка доставки. StatusCode 0, если ответа не было
+type WebhookAttempt struct {
+	Attempt    int
+	StatusCode int
+	Error      string
+	Duration   time.Duration
+	CreatedAt  time.Time
+}
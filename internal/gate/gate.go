@@ -7,9 +7,13 @@ import (
 	"github.com/menyasosali/mts/internal/server"
 	"github.com/menyasosali/mts/internal/server/gateway"
 	"github.com/menyasosali/mts/internal/service/db"
+	"github.com/menyasosali/mts/internal/service/events"
 	"github.com/menyasosali/mts/internal/service/filestorer"
+	"github.com/menyasosali/mts/internal/service/gc"
 	"github.com/menyasosali/mts/internal/service/kafka"
 	"github.com/menyasosali/mts/internal/service/minio"
+	"github.com/menyasosali/mts/internal/service/outbox"
+	"github.com/menyasosali/mts/internal/service/webhook"
 	"github.com/menyasosali/mts/pkg/logger"
 	"github.com/menyasosali/mts/pkg/postgres"
 	"log"
@@ -19,7 +23,8 @@ import (
 )
 
 func Run(cfg *config.GateConfig) {
-	ctx := context.Background()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 	// Logger
 	l := logger.NewLogger(cfg.Log.Level)
 
@@ -59,11 +64,36 @@ func Run(cfg *config.GateConfig) {
 	// DB Store
 	store := db.NewStore(l, pg)
 
+	// Outbox Relay: задачи worker'а из outbox отправляются в Kafka
+	relay := outbox.NewRelay(l, pg, kafkaProducer, cfg.Outbox)
+	relay.Start(ctx)
+
+	// GC: объекты MinIO без ссылок и зависшие изображения
+	collector := gc.NewCollector(l, minioClient, store, cfg.Kafka.Topic, cfg.GC)
+	collector.Start(ctx)
+
+	// Image events: LISTEN image_events для WatchImage
+	eventsHub := events.NewHub(l, store)
+	eventsHub.Start(ctx)
+
+	// Webhooks: уведомления о завершении обработки на callback_url изображений
+	dispatcher, err := webhook.NewDispatcher(l, store, cfg.Webhook)
+	if err != nil {
+		l.Fatal(fmt.Errorf("app - Run - webhook.NewDispatcher: %w", err))
+	}
+	dispatcher.Start(ctx)
+
 	// Transport
 	//newTransport := transport.NewTransport(l, fileStorer, store, kafkaProducer)
-	gatewayService := gateway.NewService(l, fileStorer, store, kafkaProducer)
+	gatewayService := gateway.NewService(l, fileStorer, store, eventsHub, cfg.Kafka.Topic, cfg.Render,
+		cfg.Upload, cfg.Webhook)
 	// HTTP Server
-	httpServer := server.NewServer(ctx, l, gatewayService, server.Port(cfg.HTTP.Port))
+	httpServer, err := server.NewServer(ctx, l, gatewayService, server.Port(cfg.HTTP.Port),
+		server.GRPCPort(cfg.HTTP.GRPCPort), server.ReadTimeout(cfg.HTTP.ReadTimeout),
+		server.WriteTimeout(cfg.HTTP.WriteTimeout))
+	if err != nil {
+		l.Fatal(fmt.Errorf("app - Run - server.NewServer: %w", err))
+	}
 
 	// Waiting signal
 	stop := make(chan os.Signal, 1)
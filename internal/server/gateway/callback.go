@@ -0,0 +1,42 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"google.golang.org/grpc/metadata"
+	"net/url"
+)
+
+// _callbackURLKey - адрес webhook о завершении обработки. Если загрузка его не передала, берется
+// callback_url клиента тенанта из webhook.clients
+const _callbackURLKey = "x-callback-url"
+
+var errInvalidCallbackURL = errors.New("invalid callback url")
+
+// callbackURL возвращает адрес webhook загрузки из metadata ctx, пустой - уведомление не нужно.
+// Проверяется на входе загрузки, до приема файла
+func (s *Service) callbackURL(ctx context.Context) (string, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	callback := firstValue(md, _callbackURLKey)
+	if callback == "" {
+		tenant := firstValue(md, _tenantKey)
+		for _, client := range s.WebhookCfg.Clients {
+			if tenant != "" && client.Tenant == tenant {
+				callback = client.CallbackURL
+				break
+			}
+		}
+	}
+	if callback == "" {
+		return "", nil
+	}
+
+	u, err := url.Parse(callback)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", fmt.Errorf("%w: %q must be an absolute http(s) url", errInvalidCallbackURL, callback)
+	}
+
+	return callback, nil
+}
@@ -0,0 +1,79 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/menyasosali/mts/internal/domain"
+	"github.com/menyasosali/mts/internal/service/db"
+	pb "github.com/menyasosali/mts/pkg/gen"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// DeleteImage удаляет изображение. Сначала строке ставится надгробие - для API изображения уже
+// нет, затем из MinIO удаляются превью, кэш рендера и оригинал, и только потом сама строка. Если
+// удаление прервалось, надгробие остается: повторный DELETE или GC доводят его до конца
+func (s *Service) DeleteImage(ctx context.Context, req *pb.DeleteImageRequest) (*emptypb.Empty, error) {
+	imageID := req.GetId()
+	if imageID == "" {
+		return nil, status.Error(codes.InvalidArgument, "image ID is required")
+	}
+
+	img, err := s.Store.DeleteImage(ctx, imageID)
+	if err != nil {
+		if errors.Is(err, db.ErrImageNotFound) {
+			return nil, status.Errorf(codes.NotFound, "image %s not found", imageID)
+		}
+		s.Logger.Error("Failed to delete image in db", err)
+		return nil, status.Errorf(codes.Internal, "failed to delete image: %v", err)
+	}
+
+	err = s.purgeImage(ctx, img)
+	if err != nil {
+		// изображение уже удалено для клиентов, остатки уберет GC
+		s.Logger.Warn(fmt.Sprintf("Image %s is deleted, cleanup is left to GC: %v", imageID, err))
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// purgeImage удаляет объекты надгробия из MinIO и затем строку
+func (s *Service) purgeImage(ctx context.Context, img *domain.ImgDescriptor) error {
+	keys := make([]string, 0, len(img.Variants))
+	for _, variant := range img.Variants {
+		keys = append(keys, variant.Key)
+	}
+
+	rendered, err := s.FileStorer.ListImages(ctx, renderPrefix(img.ID))
+	if err != nil {
+		return err
+	}
+	keys = append(keys, rendered...)
+
+	var failed int
+	for _, key := range keys {
+		err = s.FileStorer.DeleteImage(ctx, key)
+		if err != nil {
+			s.Logger.Error(fmt.Sprintf("Failed to delete object %s of image %s: %v", key, img.ID, err))
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to delete %d of %d objects", failed, len(keys))
+	}
+
+	err = s.Store.PurgeImage(ctx, img.ID)
+	if err != nil && !errors.Is(err, db.ErrImageNotFound) {
+		return err
+	}
+
+	// оригинал адресуется по sha256: тот же файл мог быть загружен заново уже после надгробия,
+	// тогда объект принадлежит новому изображению. Проверка и удаление - под блокировкой ключа,
+	// иначе параллельная загрузка могла бы зарегистрироваться между ними (см. settleOriginal)
+	_, err = s.Store.DeleteUnreferencedObject(ctx, img.ObjectKey, func(ctx context.Context) error {
+		return s.FileStorer.DeleteImage(ctx, img.ObjectKey)
+	})
+	return err
+}
@@ -5,46 +5,67 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"github.com/menyasosali/mts/config"
+	"github.com/menyasosali/mts/internal/domain"
 	"github.com/menyasosali/mts/internal/service/db"
 	"github.com/menyasosali/mts/internal/service/filestorer"
-	"github.com/menyasosali/mts/internal/service/kafka"
+	"github.com/menyasosali/mts/internal/service/minio"
 	pb "github.com/menyasosali/mts/pkg/gen"
 	"github.com/menyasosali/mts/pkg/logger"
 	"google.golang.org/genproto/googleapis/api/httpbody"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
-	"io"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"mime/multipart"
 	"net/http"
+	"strconv"
+	"sync"
 )
 
+// превью меняются на месте при новой нарезке (SetFocalPoint, смена пресетов): кэш каждый раз
+// проверяет ETag
+const _variantCacheControl = "public, no-cache"
+
 type ImageResponse struct {
-	ImageID     string `json:"imageID"`
-	Name        string `json:"name"`
-	OriginalURL string `json:"originalUrl"`
+	ImageID      string `json:"imageID"`
+	Name         string `json:"name"`
+	OriginalURL  string `json:"originalUrl"`
+	Deduplicated bool   `json:"deduplicated,omitempty"` // файл уже был загружен, это существующее изображение
 }
 
 type ImageDescriptorResponse struct {
-	ImageID     string `json:"imageID"`
-	OriginalURL string `json:"originalUrl"`
-	Img512      string `json:"img512"`
-	Img256      string `json:"img256"`
-	Img16       string `json:"img16"`
+	ImageID     string            `json:"imageID"`
+	OriginalURL string            `json:"originalUrl"`
+	Variants    map[string]string `json:"variants"`
 }
 
 type Service struct {
 	Logger     logger.Interface
 	FileStorer filestorer.FileStorerInterface
 	Store      db.StoreInterface
-	Producer   *kafka.ImageProducer
+	Events     ImageEvents
+	JobTopic   string
+	RenderCfg  config.RenderConfig
+	UploadCfg  config.UploadConfig
+	WebhookCfg config.WebhookConfig
 	pb.UnimplementedGatewayServer
+
+	uploadLocks sync.Map // id tus-загрузок, которые сейчас принимает PATCH
 }
 
 func NewService(log logger.Interface, fileStorer filestorer.FileStorerInterface, store db.StoreInterface,
-	producer *kafka.ImageProducer) *Service {
+	events ImageEvents, jobTopic string, renderCfg config.RenderConfig, uploadCfg config.UploadConfig,
+	webhookCfg config.WebhookConfig) *Service {
 	return &Service{
 		Logger:     log,
 		FileStorer: fileStorer,
 		Store:      store,
-		Producer:   producer,
+		Events:     events,
+		JobTopic:   jobTopic,
+		RenderCfg:  renderCfg,
+		UploadCfg:  uploadCfg,
+		WebhookCfg: webhookCfg,
 	}
 }
 
@@ -79,46 +100,271 @@ func (s *Service) GetImageByID(ctx context.Context, req *pb.GetImageByIDRequest)
 
 	img, err := s.Store.GetImageByID(ctx, imageID)
 	if err != nil {
+		if errors.Is(err, db.ErrImageNotFound) {
+			return nil, status.Errorf(codes.NotFound, "image %s not found", imageID)
+		}
 		s.Logger.Error("Failed to get image from db", err)
 		return nil, errors.New(fmt.Sprintf("Failed to get image from db: %v", err))
 	}
 
+	return imageToPb(img), nil
+}
+
+// SetFocalPoint сохраняет центр обрезки и заново ставит изображение в очередь на нарезку
+func (s *Service) SetFocalPoint(ctx context.Context, req *pb.SetFocalPointRequest) (*pb.GetImageByIDResponse, error) {
+	imageID := req.GetId()
+	if imageID == "" {
+		return nil, status.Error(codes.InvalidArgument, "image ID is required")
+	}
+	// сравнения с NaN ложны, поэтому условие записано через попадание в отрезок
+	x, y := req.GetX(), req.GetY()
+	if !(x >= 0 && x <= 1 && y >= 0 && y <= 1) {
+		return nil, status.Error(codes.InvalidArgument, "focal point coordinates must be within [0, 1]")
+	}
+
+	err := s.Store.SetFocalPoint(ctx, imageID, domain.FocalPoint{X: x, Y: y})
+	if err != nil {
+		if errors.Is(err, db.ErrImageNotFound) {
+			return nil, status.Errorf(codes.NotFound, "image %s not found", imageID)
+		}
+		s.Logger.Error("Failed to save focal point", err)
+		return nil, status.Errorf(codes.Internal, "failed to save focal point: %v", err)
+	}
+
+	// рендер cover обрезан по старому центру
+	err = s.dropRenders(ctx, imageID)
+	if err != nil {
+		s.Logger.Warn(fmt.Sprintf("Failed to drop rendered images of image %s: %v", imageID, err))
+	}
+
+	img, err := s.Store.GetImageByID(ctx, imageID)
+	if err != nil {
+		s.Logger.Error("Failed to get image from db", err)
+		return nil, status.Errorf(codes.Internal, "failed to get image from db: %v", err)
+	}
+
+	err = s.enqueueImage(ctx, *img)
+	switch {
+	case errors.Is(err, db.ErrStatusTransition):
+		// изображение уже в очереди или обрабатывается, новый центр учтет текущая задача
+		s.Logger.Info(fmt.Sprintf("Image %s is already queued, skip reprocessing", imageID))
+	case err != nil:
+		return nil, status.Errorf(codes.Internal, "failed to enqueue image: %v", err)
+	}
+
+	return s.GetImageByID(ctx, &pb.GetImageByIDRequest{Id: imageID})
+}
+
+func imageToPb(img *domain.ImgDescriptor) *pb.GetImageByIDResponse {
 	return &pb.GetImageByIDResponse{
-		ImageID:     imageID,
-		OriginalURL: img.URL,
-		Img512:      img.URL512,
-		Img256:      img.URL256,
-		Img16:       img.URL16,
-	}, nil
+		ImageID:       img.ID,
+		Name:          img.Name,
+		OriginalURL:   img.URL,
+		Variants:      variantsToPb(img.Variants),
+		FocalPoint:    focalPointToPb(img.FocalPoint),
+		Status:        imageStatusToPb(img.Status),
+		FailureReason: img.FailureReason,
+		CreatedAt:     timestamppb.New(img.CreatedAt),
+		UpdatedAt:     timestamppb.New(img.UpdatedAt),
+		CallbackURL:   img.CallbackURL,
+		Metadata:      metadataToPb(img.Metadata),
+	}
+}
+
+func metadataToPb(metadata *domain.ImageMetadata) *pb.ImageMetadata {
+	if metadata == nil {
+		return nil
+	}
+
+	result := &pb.ImageMetadata{
+		Width:      int32(metadata.Width),
+		Height:     int32(metadata.Height),
+		Format:     metadata.Format,
+		Size:       metadata.Size,
+		ColorModel: metadata.ColorModel,
+	}
+	if metadata.EXIF != nil {
+		result.EXIF = &pb.ImageEXIF{
+			CameraMake:  metadata.EXIF.CameraMake,
+			CameraModel: metadata.EXIF.CameraModel,
+			Orientation: int32(metadata.EXIF.Orientation),
+		}
+		if metadata.EXIF.CapturedAt != nil {
+			result.EXIF.CapturedAt = timestamppb.New(*metadata.EXIF.CapturedAt)
+		}
+		if metadata.EXIF.GPS != nil {
+			result.EXIF.GPS = &pb.GPSLocation{
+				Latitude:  metadata.EXIF.GPS.Latitude,
+				Longitude: metadata.EXIF.GPS.Longitude,
+			}
+		}
+	}
+
+	return result
+}
+
+func focalPointToPb(focal *domain.FocalPoint) *pb.FocalPoint {
+	if focal == nil {
+		return nil
+	}
+	return &pb.FocalPoint{X: focal.X, Y: focal.Y}
+}
+
+func variantsToPb(variants []domain.Variant) []*pb.Variant {
+	result := make([]*pb.Variant, 0, len(variants))
+	for _, variant := range variants {
+		result = append(result, &pb.Variant{
+			Name:        variant.Preset,
+			URL:         variant.URL,
+			Width:       int32(variant.Width),
+			Height:      int32(variant.Height),
+			Format:      variant.Format,
+			ContentType: variant.ContentType,
+		})
+	}
+	return result
+}
+
+func imageStatusToPb(status domain.ImageStatus) pb.ImageStatus {
+	switch status {
+	case domain.StatusUploaded:
+		return pb.ImageStatus_IMAGE_STATUS_UPLOADED
+	case domain.StatusQueued:
+		return pb.ImageStatus_IMAGE_STATUS_QUEUED
+	case domain.StatusProcessing:
+		return pb.ImageStatus_IMAGE_STATUS_PROCESSING
+	case domain.StatusReady:
+		return pb.ImageStatus_IMAGE_STATUS_READY
+	case domain.StatusFailed:
+		return pb.ImageStatus_IMAGE_STATUS_FAILED
+	case domain.StatusDeleted:
+		return pb.ImageStatus_IMAGE_STATUS_DELETED
+	default:
+		return pb.ImageStatus_IMAGE_STATUS_UNSPECIFIED
+	}
 }
 
+func imageStatusFromPb(status pb.ImageStatus) domain.ImageStatus {
+	switch status {
+	case pb.ImageStatus_IMAGE_STATUS_UPLOADED:
+		return domain.StatusUploaded
+	case pb.ImageStatus_IMAGE_STATUS_QUEUED:
+		return domain.StatusQueued
+	case pb.ImageStatus_IMAGE_STATUS_PROCESSING:
+		return domain.StatusProcessing
+	case pb.ImageStatus_IMAGE_STATUS_READY:
+		return domain.StatusReady
+	case pb.ImageStatus_IMAGE_STATUS_FAILED:
+		return domain.StatusFailed
+	default:
+		return ""
+	}
+}
+
+// GetVariantHandler отдает превью пресета из MinIO, выбирая кодировку по заголовку Accept
+func (s *Service) GetVariantHandler(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+	imageID, preset := pathParams["id"], pathParams["preset"]
+
+	img, err := s.Store.GetImageByID(r.Context(), imageID)
+	if err != nil {
+		if errors.Is(err, db.ErrImageNotFound) {
+			http.Error(w, "Image not found", http.StatusNotFound)
+			return
+		}
+		s.Logger.Error("Failed to get image from db", err)
+		http.Error(w, "Failed to get image from db", http.StatusInternalServerError)
+		return
+	}
+
+	var variants []domain.Variant
+	for _, variant := range img.Variants {
+		if variant.Preset == preset {
+			variants = append(variants, variant)
+		}
+	}
+
+	variant, ok := selectVariant(variants, r.Header.Get("Accept"))
+	if !ok {
+		http.Error(w, "Variant not found", http.StatusNotFound)
+		return
+	}
+
+	object, err := s.FileStorer.OpenImage(r.Context(), variant.Key)
+	if err != nil {
+		if errors.Is(err, minio.ErrFileNotFound) {
+			http.Error(w, "Variant not found", http.StatusNotFound)
+			return
+		}
+		s.Logger.Error("Failed to open variant", err)
+		http.Error(w, "Failed to open variant", http.StatusInternalServerError)
+		return
+	}
+	defer object.Close()
+
+	contentType := variant.ContentType
+	if contentType == "" {
+		contentType = object.ContentType
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Vary", "Accept")
+	w.Header().Set("ETag", strconv.Quote(object.ETag))
+	w.Header().Set("Cache-Control", _variantCacheControl)
+
+	// ServeContent обрабатывает If-None-Match, Range и HEAD
+	http.ServeContent(w, r, "", object.LastModified, object)
+}
+
+// nextFilePart пропускает части формы до файла с именем поля field
+func nextFilePart(reader *multipart.Reader, field string) (*multipart.Part, error) {
+	for {
+		part, err := reader.NextPart()
+		if err != nil {
+			return nil, fmt.Errorf("form file %q not found: %w", field, err)
+		}
+		if part.FormName() == field && part.FileName() != "" {
+			return part, nil
+		}
+		part.Close()
+	}
+}
+
+// UploadImageHandler читает multipart потоком и сразу отдает файл в MinIO, не держа его в памяти
 func (s *Service) UploadImageHandler(w http.ResponseWriter, r *http.Request, _ map[string]string) {
-	err := r.ParseMultipartForm(10 << 20) // 10MB
+	r.Body = http.MaxBytesReader(w, r.Body, s.UploadCfg.MaxSize)
+
+	reader, err := r.MultipartReader()
 	if err != nil {
 		s.Logger.Error("Failed to parse multipart form", err)
 		http.Error(w, "Failed to parse multipart form", http.StatusBadRequest)
 		return
 	}
 	s.Logger.Info("92.. - producer.go - Parse - success")
-	file, header, err := r.FormFile("image")
+
+	part, err := nextFilePart(reader, "image")
 	if err != nil {
 		s.Logger.Error("Failed to read uploaded file", err)
 		http.Error(w, "Failed to read uploaded file", http.StatusBadRequest)
 		return
 	}
-	defer file.Close()
+	defer part.Close()
+
+	filename := part.FileName()
+	ctx := requestContext(r)
 
-	imageBytes, err := io.ReadAll(file)
+	_, err = s.callbackURL(ctx)
 	if err != nil {
-		s.Logger.Error("Failed to read image bytes", err)
-		http.Error(w, "Failed to read image bytes", http.StatusInternalServerError)
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	filename := header.Filename
-
-	imgURL, err := s.FileStorer.UploadImage(r.Context(), imageBytes, filename)
+	staged, err := s.stageOriginal(ctx, part, -1, part.Header.Get("Content-Type"))
 	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			http.Error(w, fmt.Sprintf("File exceeds %d bytes", s.UploadCfg.MaxSize), http.StatusRequestEntityTooLarge)
+			return
+		}
 		s.Logger.Error("Failed to upload image", err)
 		http.Error(w, "Failed to upload image", http.StatusInternalServerError)
 		return
@@ -126,32 +372,18 @@ func (s *Service) UploadImageHandler(w http.ResponseWriter, r *http.Request, _ m
 
 	s.Logger.Info("117.. - producer.go - FileStorer Upload - success")
 
-	imgID, err := s.Store.UploadImage(r.Context(), filename, imgURL)
+	original, err := s.promoteOriginal(ctx, staged.TmpKey, staged.SHA256)
 	if err != nil {
-		s.Logger.Error("Failed to save image to db", err)
-		http.Error(w, "Failed to save image to db", http.StatusInternalServerError)
-		return
-	}
-
-	response := ImageResponse{
-		ImageID:     imgID,
-		Name:        filename,
-		OriginalURL: imgURL,
-	}
-
-	message, err := json.Marshal(response)
-	if err != nil {
-		s.Logger.Error("Failed to marshal response to JSON", err)
-		http.Error(w, "Failed to marshal response to JSON", http.StatusInternalServerError)
+		s.Logger.Error("Failed to store original", err)
+		http.Error(w, "Failed to upload image", http.StatusInternalServerError)
 		return
 	}
-	s.Logger.Info(fmt.Sprintf("138.. - producer.go - message: %s", message))
+	original.Name = filename
 
-	// в producer кидаю response в topic
-	err = s.Producer.ProduceMessage(r.Context(), message)
+	response, err := s.registerOriginal(ctx, staged.TmpKey, original)
 	if err != nil {
-		s.Logger.Error("Failed to produce message to Kafka topic", err)
-		http.Error(w, "Failed to produce message to Kafka topic", http.StatusInternalServerError)
+		s.Logger.Error("Failed to register image", err)
+		http.Error(w, "Failed to register image", http.StatusInternalServerError)
 		return
 	}
 
@@ -160,3 +392,14 @@ func (s *Service) UploadImageHandler(w http.ResponseWriter, r *http.Request, _ m
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
 }
+
+// enqueueImage ставит изображение в очередь worker'а: статус queued и задача в outbox сохраняются
+// одной транзакцией, в Kafka задачу отправит outbox.Relay
+func (s *Service) enqueueImage(ctx context.Context, img domain.ImgDescriptor) error {
+	job, err := s.jobMessage(ctx, img)
+	if err != nil {
+		return err
+	}
+
+	return s.Store.QueueImage(ctx, img.ID, job)
+}
@@ -0,0 +1,59 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"github.com/menyasosali/mts/internal/domain"
+	"github.com/menyasosali/mts/internal/service/kafka"
+	"github.com/menyasosali/mts/internal/service/outbox"
+	"google.golang.org/grpc/metadata"
+	"net/http"
+)
+
+// Тенант и W3C trace context задачи берутся из входящей gRPC metadata. Через grpc-gateway их
+// передают заголовками Grpc-Metadata-*, обработчики HandlePath переносят заголовки сами (requestContext)
+const (
+	_tenantKey      = "x-tenant-id"
+	_traceparentKey = "traceparent"
+	_tracestateKey  = "tracestate"
+)
+
+func newImageJob(ctx context.Context, img domain.ImgDescriptor) kafka.ImageJob {
+	job := kafka.NewImageJob(img)
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	job.Tenant = firstValue(md, _tenantKey)
+	job.TraceParent = firstValue(md, _traceparentKey)
+	job.TraceState = firstValue(md, _tracestateKey)
+
+	return job
+}
+
+// jobMessage собирает сообщение outbox с задачей worker'а, ключ - id изображения
+func (s *Service) jobMessage(ctx context.Context, img domain.ImgDescriptor) (outbox.Message, error) {
+	payload, err := newImageJob(ctx, img).Marshal()
+	if err != nil {
+		return outbox.Message{}, fmt.Errorf("failed to marshal image job: %w", err)
+	}
+
+	return outbox.Message{Topic: s.JobTopic, Key: img.ID, Payload: payload}, nil
+}
+
+// requestContext переносит заголовки тенанта, трассировки и callback url HTTP-запроса в metadata контекста
+func requestContext(r *http.Request) context.Context {
+	md := metadata.MD{}
+	for _, key := range []string{_tenantKey, _traceparentKey, _tracestateKey, _callbackURLKey} {
+		if value := r.Header.Get(key); value != "" {
+			md.Set(key, value)
+		}
+	}
+	return metadata.NewIncomingContext(r.Context(), md)
+}
+
+func firstValue(md metadata.MD, key string) string {
+	values := md.Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
@@ -0,0 +1,206 @@
+package gateway
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"github.com/menyasosali/mts/internal/service/db"
+	pb "github.com/menyasosali/mts/pkg/gen"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"time"
+)
+
+const (
+	_defaultPageSize = 50
+	_maxPageSize     = 500
+	_maxBatchSize    = 100
+)
+
+// pageToken - содержимое page_token. Клиенту он непрозрачен: base64 от JSON с ключом последней строки
+// страницы, порядком и фильтрами, с которыми токен выдан
+type pageToken struct {
+	Order  db.ImageOrder  `json:"o"`
+	Filter db.ImageFilter `json:"f"`
+	After  db.ImageCursor `json:"a"`
+}
+
+func (t pageToken) encode() string {
+	data, _ := json.Marshal(t)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// matches проверяет, что токен выдан для тех же фильтров и порядка
+func (t pageToken) matches(order db.ImageOrder, filter db.ImageFilter) bool {
+	return t.Order == order &&
+		t.Filter.NamePrefix == filter.NamePrefix &&
+		t.Filter.Status == filter.Status &&
+		t.Filter.CreatedAfter.Equal(filter.CreatedAfter) &&
+		t.Filter.CreatedBefore.Equal(filter.CreatedBefore)
+}
+
+func decodePageToken(token string) (pageToken, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return pageToken{}, err
+	}
+
+	var t pageToken
+	err = json.Unmarshal(data, &t)
+	return t, err
+}
+
+// ListImages отдает изображения страницами. Следующая страница запрашивается с next_page_token,
+// выборка идет по ключу последней строки, поэтому новые загрузки не сдвигают уже выданные страницы
+func (s *Service) ListImages(ctx context.Context, req *pb.ListImagesRequest) (*pb.ListImagesResponse, error) {
+	pageSize := int(req.GetPageSize())
+	switch {
+	case pageSize < 0:
+		return nil, status.Error(codes.InvalidArgument, "page_size must not be negative")
+	case pageSize == 0:
+		pageSize = _defaultPageSize
+	case pageSize > _maxPageSize:
+		pageSize = _maxPageSize
+	}
+
+	filter, err := imageFilterFromPb(req)
+	if err != nil {
+		return nil, err
+	}
+	order := imageOrderFromPb(req.GetOrder())
+
+	var after *db.ImageCursor
+	if req.GetPageToken() != "" {
+		token, err := decodePageToken(req.GetPageToken())
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid page_token")
+		}
+		if !token.matches(order, filter) {
+			return nil, status.Error(codes.InvalidArgument, "page_token was issued for other filters or order")
+		}
+		after = &token.After
+	}
+
+	// лишняя строка показывает, есть ли следующая страница
+	images, err := s.Store.ListImages(ctx, filter, order, after, pageSize+1)
+	if err != nil {
+		s.Logger.Error("Failed to list images from db", err)
+		return nil, status.Errorf(codes.Internal, "failed to list images: %v", err)
+	}
+
+	response := &pb.ListImagesResponse{}
+	if len(images) > pageSize {
+		images = images[:pageSize]
+		last := images[len(images)-1]
+		response.NextPageToken = pageToken{
+			Order:  order,
+			Filter: filter,
+			After:  db.ImageCursor{ID: last.ID, Name: last.Name, CreatedAt: last.CreatedAt},
+		}.encode()
+	}
+
+	response.Images = make([]*pb.GetImageByIDResponse, 0, len(images))
+	for i := range images {
+		response.Images = append(response.Images, imageToPb(&images[i]))
+	}
+
+	return response, nil
+}
+
+func imageFilterFromPb(req *pb.ListImagesRequest) (db.ImageFilter, error) {
+	filter := db.ImageFilter{
+		NamePrefix: req.GetNamePrefix(),
+		Status:     imageStatusFromPb(req.GetStatus()),
+	}
+
+	if req.GetStatus() != pb.ImageStatus_IMAGE_STATUS_UNSPECIFIED && filter.Status == "" {
+		return db.ImageFilter{}, status.Errorf(codes.InvalidArgument, "unknown status %v", req.GetStatus())
+	}
+
+	var err error
+	filter.CreatedAfter, err = timeFromPb(req.GetCreatedAfter())
+	if err != nil {
+		return db.ImageFilter{}, status.Errorf(codes.InvalidArgument, "invalid created_after: %v", err)
+	}
+
+	filter.CreatedBefore, err = timeFromPb(req.GetCreatedBefore())
+	if err != nil {
+		return db.ImageFilter{}, status.Errorf(codes.InvalidArgument, "invalid created_before: %v", err)
+	}
+
+	return filter, nil
+}
+
+func timeFromPb(ts *timestamppb.Timestamp) (time.Time, error) {
+	if ts == nil {
+		return time.Time{}, nil
+	}
+
+	err := ts.CheckValid()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return ts.AsTime(), nil
+}
+
+func imageOrderFromPb(order pb.ImageOrder) db.ImageOrder {
+	switch order {
+	case pb.ImageOrder_IMAGE_ORDER_CREATED_AT_ASC:
+		return db.OrderCreatedAtAsc
+	case pb.ImageOrder_IMAGE_ORDER_NAME_ASC:
+		return db.OrderNameAsc
+	case pb.ImageOrder_IMAGE_ORDER_NAME_DESC:
+		return db.OrderNameDesc
+	default:
+		return db.OrderCreatedAtDesc
+	}
+}
+
+// BatchGetImages отдает несколько изображений одним запросом к базе. Ненайденные id возвращаются
+// в MissingIDs, а не ошибкой
+func (s *Service) BatchGetImages(ctx context.Context, req *pb.BatchGetImagesRequest,
+) (*pb.BatchGetImagesResponse, error) {
+	ids := make([]string, 0, len(req.GetIds()))
+	seen := make(map[string]bool, len(req.GetIds()))
+	for _, id := range req.GetIds() {
+		if id == "" {
+			return nil, status.Error(codes.InvalidArgument, "image ID is required")
+		}
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	if len(ids) > _maxBatchSize {
+		return nil, status.Errorf(codes.InvalidArgument, "at most %d ids are allowed, got %d", _maxBatchSize, len(ids))
+	}
+
+	response := &pb.BatchGetImagesResponse{}
+	if len(ids) == 0 {
+		return response, nil
+	}
+
+	images, err := s.Store.GetImagesByIDs(ctx, ids)
+	if err != nil {
+		s.Logger.Error("Failed to get images from db", err)
+		return nil, status.Errorf(codes.Internal, "failed to get images: %v", err)
+	}
+
+	found := make(map[string]bool, len(images))
+	response.Images = make([]*pb.GetImageByIDResponse, 0, len(images))
+	for i := range images {
+		found[images[i].ID] = true
+		response.Images = append(response.Images, imageToPb(&images[i]))
+	}
+
+	for _, id := range ids {
+		if !found[id] {
+			response.MissingIDs = append(response.MissingIDs, id)
+		}
+	}
+
+	return response, nil
+}
@@ -0,0 +1,69 @@
+package gateway
+
+import (
+	"github.com/menyasosali/mts/internal/domain"
+	"strconv"
+	"strings"
+)
+
+// _preferredFormats - современные форматы в порядке предпочтения, отдаются только если клиент
+// явно перечислил их в Accept (image/* и */* браузеры шлют и без поддержки AVIF)
+var _preferredFormats = []string{"avif", "webp"}
+
+// selectVariant выбирает лучшую кодировку пресета для Accept: AVIF > WebP > формат оригинала
+func selectVariant(variants []domain.Variant, accept string) (domain.Variant, bool) {
+	if len(variants) == 0 {
+		return domain.Variant{}, false
+	}
+
+	accepted := parseAccept(accept)
+	for _, format := range _preferredFormats {
+		for _, variant := range variants {
+			if variant.Format == format && accepted[variant.ContentType] > 0 {
+				return variant, true
+			}
+		}
+	}
+
+	for _, variant := range variants {
+		if !isPreferredFormat(variant.Format) {
+			return variant, true
+		}
+	}
+
+	return variants[0], true
+}
+
+// parseAccept возвращает q-значения явно перечисленных типов
+func parseAccept(accept string) map[string]float64 {
+	accepted := make(map[string]float64)
+	for _, part := range strings.Split(accept, ",") {
+		fields := strings.Split(part, ";")
+		mediaType := strings.ToLower(strings.TrimSpace(fields[0]))
+		if mediaType == "" {
+			continue
+		}
+
+		q := 1.0
+		for _, param := range fields[1:] {
+			key, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+			if ok && strings.EqualFold(key, "q") {
+				parsed, err := strconv.ParseFloat(value, 64)
+				if err == nil {
+					q = parsed
+				}
+			}
+		}
+		accepted[mediaType] = q
+	}
+	return accepted
+}
+
+func isPreferredFormat(format string) bool {
+	for _, preferred := range _preferredFormats {
+		if format == preferred {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,198 @@
+package gateway
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/menyasosali/mts/internal/domain"
+	"github.com/menyasosali/mts/internal/service/db"
+	"github.com/menyasosali/mts/internal/service/minio"
+	"hash"
+	"io"
+)
+
+// Оригиналы адресуются по содержимому: загрузка сначала пишется во временный ключ uploads/<uuid>
+// с подсчетом sha256, затем копируется в originals/<sha256>. Повторная загрузка того же файла
+// возвращает уже существующее изображение и не ставит его в очередь worker'а
+
+func tmpOriginalKey(id string) string {
+	return "uploads/" + id
+}
+
+func originalKey(sha256 string) string {
+	return "originals/" + sha256
+}
+
+// stagedOriginal - оригинал во временном ключе
+type stagedOriginal struct {
+	TmpKey string
+	SHA256 string
+	Size   int64
+}
+
+// stageOriginal потоково загружает файл во временный ключ, считая его размер и sha256
+func (s *Service) stageOriginal(ctx context.Context, file io.Reader, size int64, contentType string,
+) (stagedOriginal, error) {
+	reader := &hashingReader{Reader: file, hash: sha256.New()}
+	staged := stagedOriginal{TmpKey: tmpOriginalKey(uuid.New().String())}
+
+	_, err := s.FileStorer.UploadImage(ctx, reader, size, staged.TmpKey, contentType)
+	if err != nil {
+		return stagedOriginal{}, err
+	}
+
+	staged.SHA256 = hex.EncodeToString(reader.hash.Sum(nil))
+	staged.Size = reader.size
+	return staged, nil
+}
+
+func (s *Service) discardOriginal(ctx context.Context, tmpKey string) {
+	err := s.FileStorer.DeleteImage(ctx, tmpKey)
+	if err != nil {
+		s.Logger.Error(fmt.Sprintf("Failed to delete temporary original %s: %v", tmpKey, err))
+	}
+}
+
+// promoteOriginal переносит оригинал из временного ключа в originals/<sha256>, если такого объекта
+// еще нет. Временный объект остается до settleOriginal: пока на оригинал не ссылается база, его может
+// удалить purgeImage или GC, и тогда он восстанавливается из временного
+func (s *Service) promoteOriginal(ctx context.Context, tmpKey, sha256 string) (domain.ImgDescriptor, error) {
+	key := originalKey(sha256)
+
+	_, err := s.FileStorer.StatImage(ctx, key)
+	switch {
+	case errors.Is(err, minio.ErrFileNotFound):
+		err = s.FileStorer.CopyImage(ctx, tmpKey, key)
+		if err != nil {
+			return domain.ImgDescriptor{}, err
+		}
+	case err != nil:
+		return domain.ImgDescriptor{}, err
+	}
+
+	imgURL, err := s.FileStorer.ImageURL(ctx, key)
+	if err != nil {
+		return domain.ImgDescriptor{}, err
+	}
+
+	return domain.ImgDescriptor{URL: imgURL, ObjectKey: key, SHA256: sha256}, nil
+}
+
+// settleOriginal вызывается, когда на оригинал key уже ссылается база. Проверка идет под той же
+// блокировкой, что и удаление объектов без ссылок (db.Store.DeleteUnreferencedObject): объект,
+// удаленный до появления ссылки, копируется заново из временного tmpKey
+func (s *Service) settleOriginal(ctx context.Context, tmpKey, key string) error {
+	err := s.Store.LockObject(ctx, key, func(ctx context.Context) error {
+		_, err := s.FileStorer.StatImage(ctx, key)
+		if errors.Is(err, minio.ErrFileNotFound) {
+			s.Logger.Warn(fmt.Sprintf("Original %s was deleted before registration, restore it from %s", key, tmpKey))
+			return s.FileStorer.CopyImage(ctx, tmpKey, key)
+		}
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to settle original %s: %w", key, err)
+	}
+
+	s.discardOriginal(ctx, tmpKey)
+	return nil
+}
+
+// registerOriginal регистрирует оригинал, перенесенный promoteOriginal из tmpKey, и убирает
+// временный объект
+func (s *Service) registerOriginal(ctx context.Context, tmpKey string, img domain.ImgDescriptor,
+) (ImageResponse, error) {
+	response, err := s.registerImage(ctx, img)
+	if err != nil {
+		s.discardOriginal(ctx, tmpKey)
+		return ImageResponse{}, err
+	}
+
+	err = s.settleOriginal(ctx, tmpKey, img.ObjectKey)
+	if err != nil {
+		return ImageResponse{}, err
+	}
+
+	return response, nil
+}
+
+// registerImage сохраняет загруженный оригинал в базе и ставит его в очередь worker'а. Если
+// изображение с тем же sha256 уже есть, возвращается оно, а webhook загрузки подписывается на него
+func (s *Service) registerImage(ctx context.Context, img domain.ImgDescriptor) (ImageResponse, error) {
+	var err error
+	img.CallbackURL, err = s.callbackURL(ctx)
+	if err != nil {
+		return ImageResponse{}, err
+	}
+
+	existing, err := s.Store.GetImageByHash(ctx, img.SHA256)
+	switch {
+	case err == nil:
+		return s.deduplicated(ctx, existing, img.CallbackURL)
+	case !errors.Is(err, db.ErrImageNotFound):
+		return ImageResponse{}, err
+	}
+
+	if img.ID == "" {
+		img.ID = uuid.New().String()
+	}
+
+	job, err := s.jobMessage(ctx, img)
+	if err != nil {
+		return ImageResponse{}, err
+	}
+
+	// изображение и задача worker'а сохраняются одной транзакцией, в Kafka задачу отправит outbox.Relay
+	img.ID, err = s.Store.UploadImage(ctx, img, job)
+	if err != nil {
+		if errors.Is(err, db.ErrImageExists) {
+			// тот же файл параллельно загрузил другой запрос
+			existing, errHash := s.Store.GetImageByHash(ctx, img.SHA256)
+			if errHash == nil {
+				return s.deduplicated(ctx, existing, img.CallbackURL)
+			}
+		}
+		return ImageResponse{}, fmt.Errorf("failed to save image to db: %w", err)
+	}
+
+	return ImageResponse{
+		ImageID:     img.ID,
+		Name:        img.Name,
+		OriginalURL: img.URL,
+	}, nil
+}
+
+// deduplicated отвечает существующим изображением. Адрес webhook загрузки не теряется: если
+// изображение уже обработано, доставка ставится сразу, иначе - по завершении обработки
+func (s *Service) deduplicated(ctx context.Context, img *domain.ImgDescriptor, callbackURL string,
+) (ImageResponse, error) {
+	if callbackURL != "" {
+		err := s.Store.AddImageCallback(ctx, img.ID, callbackURL)
+		if err != nil {
+			return ImageResponse{}, fmt.Errorf("failed to subscribe callback to existing image: %w", err)
+		}
+	}
+
+	return ImageResponse{
+		ImageID:      img.ID,
+		Name:         img.Name,
+		OriginalURL:  img.URL,
+		Deduplicated: true,
+	}, nil
+}
+
+type hashingReader struct {
+	io.Reader
+	hash hash.Hash
+	size int64
+}
+
+func (r *hashingReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	r.hash.Write(p[:n])
+	r.size += int64(n)
+	return n, err
+}
@@ -0,0 +1,146 @@
+package gateway
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/menyasosali/mts/internal/service/db"
+	"github.com/menyasosali/mts/internal/service/minio"
+	pb "github.com/menyasosali/mts/pkg/gen"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Прямая загрузка: CreateUpload резервирует id и выдает подписанный PUT url в MinIO, клиент
+// загружает оригинал сам во временный ключ uploads/<id>. Тип и размер из CreateUpload входят в
+// подпись url, поэтому MinIO примет только заявленный объект. CompleteUpload проверяет объект,
+// считает sha256 (единственный раз, когда данные проходят через gateway) и запускает обычный pipeline
+
+// CreateUpload выдает id изображения и url для загрузки оригинала в MinIO
+func (s *Service) CreateUpload(ctx context.Context, req *pb.CreateUploadRequest) (*pb.CreateUploadResponse, error) {
+	if req.GetFilename() == "" {
+		return nil, status.Error(codes.InvalidArgument, "filename is required")
+	}
+	if !strings.HasPrefix(req.GetContentType(), "image/") {
+		return nil, status.Error(codes.InvalidArgument, "content type must be image/*")
+	}
+	if req.GetSize() <= 0 || req.GetSize() > s.UploadCfg.MaxSize {
+		return nil, status.Errorf(codes.InvalidArgument, "size must be within (0, %d]", s.UploadCfg.MaxSize)
+	}
+
+	imageID := uuid.New().String()
+	expiresAt := time.Now().Add(s.UploadCfg.PresignExpiry)
+
+	uploadURL, err := s.FileStorer.PresignUpload(ctx, tmpOriginalKey(imageID), req.GetContentType(), req.GetSize(),
+		s.UploadCfg.PresignExpiry)
+	if err != nil {
+		s.Logger.Error("Failed to presign upload", err)
+		return nil, status.Errorf(codes.Internal, "failed to presign upload: %v", err)
+	}
+
+	return &pb.CreateUploadResponse{
+		ImageID:   imageID,
+		UploadURL: uploadURL,
+		Headers: map[string]string{
+			"Content-Type":   req.GetContentType(),
+			"Content-Length": strconv.FormatInt(req.GetSize(), 10),
+		},
+		ExpiresAt: timestamppb.New(expiresAt),
+	}, nil
+}
+
+// CompleteUpload проверяет загруженный клиентом объект, сохраняет изображение и ставит его в очередь.
+// Объект, не прошедший проверку, удаляется. Если такой файл уже загружен, возвращается существующее
+// изображение с другим id
+func (s *Service) CompleteUpload(ctx context.Context, req *pb.CompleteUploadRequest) (*pb.GetImageByIDResponse, error) {
+	imageID := req.GetImageId()
+	if _, err := uuid.Parse(imageID); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid image ID")
+	}
+	if req.GetFilename() == "" {
+		return nil, status.Error(codes.InvalidArgument, "filename is required")
+	}
+	if _, err := s.callbackURL(ctx); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	_, err := s.Store.GetImageByID(ctx, imageID)
+	switch {
+	case err == nil:
+		return nil, status.Errorf(codes.AlreadyExists, "image %s already exists", imageID)
+	case !errors.Is(err, db.ErrImageNotFound):
+		return nil, status.Errorf(codes.Internal, "failed to get image from db: %v", err)
+	}
+
+	tmpKey := tmpOriginalKey(imageID)
+
+	info, err := s.FileStorer.StatImage(ctx, tmpKey)
+	if err != nil {
+		if errors.Is(err, minio.ErrFileNotFound) {
+			return nil, status.Errorf(codes.FailedPrecondition, "image %s is not uploaded", imageID)
+		}
+		s.Logger.Error("Failed to stat uploaded image", err)
+		return nil, status.Errorf(codes.Internal, "failed to stat uploaded image: %v", err)
+	}
+
+	switch {
+	case info.Size > s.UploadCfg.MaxSize:
+		return nil, s.rejectUpload(ctx, tmpKey, fmt.Sprintf("image exceeds %d bytes", s.UploadCfg.MaxSize))
+	case req.GetSize() > 0 && info.Size != req.GetSize():
+		return nil, s.rejectUpload(ctx, tmpKey,
+			fmt.Sprintf("uploaded size %d does not match expected %d", info.Size, req.GetSize()))
+	case !strings.HasPrefix(info.ContentType, "image/"):
+		return nil, s.rejectUpload(ctx, tmpKey, fmt.Sprintf("content type %q is not an image", info.ContentType))
+	}
+
+	sum, err := s.hashObject(ctx, tmpKey)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to hash uploaded image: %v", err)
+	}
+
+	original, err := s.promoteOriginal(ctx, tmpKey, sum)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to store original: %v", err)
+	}
+	original.ID = imageID
+	original.Name = req.GetFilename()
+
+	response, err := s.registerOriginal(ctx, tmpKey, original)
+	if err != nil {
+		if errors.Is(err, db.ErrImageExists) {
+			return nil, status.Errorf(codes.AlreadyExists, "image %s already exists", imageID)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to register image: %v", err)
+	}
+
+	return s.GetImageByID(ctx, &pb.GetImageByIDRequest{Id: response.ImageID})
+}
+
+func (s *Service) hashObject(ctx context.Context, key string) (string, error) {
+	object, err := s.FileStorer.OpenImage(ctx, key)
+	if err != nil {
+		return "", err
+	}
+	defer object.Close()
+
+	digest := sha256.New()
+	_, err = io.Copy(digest, object)
+	if err != nil {
+		return "", fmt.Errorf("failed to read image %s: %w", key, err)
+	}
+
+	return hex.EncodeToString(digest.Sum(nil)), nil
+}
+
+func (s *Service) rejectUpload(ctx context.Context, tmpKey, reason string) error {
+	s.discardOriginal(ctx, tmpKey)
+	return status.Error(codes.InvalidArgument, reason)
+}
@@ -0,0 +1,212 @@
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/menyasosali/mts/internal/domain"
+	"github.com/menyasosali/mts/internal/service/db"
+	"github.com/menyasosali/mts/internal/service/minio"
+	"github.com/menyasosali/mts/internal/service/resizer"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// URL рендера не меняется вместе с центром обрезки, поэтому кэш проверяет ETag
+const _renderCacheControl = "public, no-cache"
+
+// RenderParams - параметры ресайза на лету в том виде, в каком они пришли в query
+type RenderParams struct {
+	ImageID string
+	Width   string
+	Height  string
+	Fit     string
+	Format  string
+}
+
+// SignRender считает подпись для GET /images/{id}/render: hex(HMAC-SHA256(secret, "id|w|h|fit|fmt"))
+func SignRender(secret string, params RenderParams) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strings.Join([]string{params.ImageID, params.Width, params.Height, params.Fit, params.Format}, "|")))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// RenderHandler ресайзит оригинал под произвольный размер и кэширует результат в MinIO.
+// Без валидной подписи sig запрос отклоняется, чтобы нельзя было нагрузить worker'ы любыми размерами
+func (s *Service) RenderHandler(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+	query := r.URL.Query()
+	params := RenderParams{
+		ImageID: pathParams["id"],
+		Width:   query.Get("w"),
+		Height:  query.Get("h"),
+		Fit:     query.Get("fit"),
+		Format:  query.Get("fmt"),
+	}
+
+	if !s.validRenderSignature(params, query) {
+		http.Error(w, "Invalid signature", http.StatusForbidden)
+		return
+	}
+
+	preset, format, err := s.parseRenderParams(params)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	img, err := s.Store.GetImageByID(r.Context(), params.ImageID)
+	if err != nil {
+		if errors.Is(err, db.ErrImageNotFound) {
+			http.Error(w, "Image not found", http.StatusNotFound)
+			return
+		}
+		s.Logger.Error("Failed to get image from db", err)
+		http.Error(w, "Failed to get image from db", http.StatusInternalServerError)
+		return
+	}
+
+	key := renderKey(img, preset, format)
+
+	object, err := s.FileStorer.OpenImage(r.Context(), key)
+	if err == nil {
+		defer object.Close()
+		w.Header().Set("Content-Type", object.ContentType)
+		w.Header().Set("ETag", strconv.Quote(object.ETag))
+		w.Header().Set("Cache-Control", _renderCacheControl)
+		http.ServeContent(w, r, "", object.LastModified, object)
+		return
+	}
+	if !errors.Is(err, minio.ErrFileNotFound) {
+		s.Logger.Error("Failed to open rendered image", err)
+		http.Error(w, "Failed to open rendered image", http.StatusInternalServerError)
+		return
+	}
+
+	original, err := s.FileStorer.DownloadImage(r.Context(), img.ObjectKey)
+	if err != nil {
+		http.Error(w, "Failed to download original image", http.StatusInternalServerError)
+		return
+	}
+
+	data, resolved, err := resizer.Render(original, preset, format, img.FocalPoint)
+	if err != nil {
+		s.Logger.Error("Failed to render image", err)
+		http.Error(w, "Failed to render image", http.StatusUnprocessableEntity)
+		return
+	}
+
+	_, err = s.FileStorer.UploadImage(r.Context(), bytes.NewReader(data), int64(len(data)), key, resolved.ContentType())
+	if err != nil {
+		// кэш не обязателен, отдаем результат и без него
+		s.Logger.Error("Failed to cache rendered image", err)
+	}
+
+	sum := md5.Sum(data)
+	w.Header().Set("Content-Type", resolved.ContentType())
+	w.Header().Set("ETag", strconv.Quote(hex.EncodeToString(sum[:])))
+	w.Header().Set("Cache-Control", _renderCacheControl)
+	http.ServeContent(w, r, "", time.Now(), bytes.NewReader(data))
+}
+
+// renderPrefix - префикс кэша рендера изображения в MinIO
+func renderPrefix(imageID string) string {
+	return "render/" + imageID + "/"
+}
+
+// renderKey - ключ кэша рендера. Центр обрезки входит в ключ: после SetFocalPoint рендер cover
+// считается заново, даже если старый кэш не удалось удалить
+func renderKey(img *domain.ImgDescriptor, preset resizer.Preset, format resizer.Format) string {
+	key := fmt.Sprintf("%s%dx%d-%s", renderPrefix(img.ID), preset.Width, preset.Height, preset.Fit)
+	if preset.Fit == resizer.FitCover && img.FocalPoint != nil {
+		key += "-" + strconv.FormatFloat(img.FocalPoint.X, 'f', -1, 64) + "," +
+			strconv.FormatFloat(img.FocalPoint.Y, 'f', -1, 64)
+	}
+	return key + "." + string(format)
+}
+
+// dropRenders удаляет кэш рендера изображения, следующие запросы отрендерят его заново
+func (s *Service) dropRenders(ctx context.Context, imageID string) error {
+	keys, err := s.FileStorer.ListImages(ctx, renderPrefix(imageID))
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		err = s.FileStorer.DeleteImage(ctx, key)
+		if err != nil {
+			return fmt.Errorf("failed to delete rendered image %s: %w", key, err)
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) validRenderSignature(params RenderParams, query url.Values) bool {
+	sig, err := hex.DecodeString(query.Get("sig"))
+	if err != nil || len(sig) == 0 {
+		return false
+	}
+
+	expected, _ := hex.DecodeString(SignRender(s.RenderCfg.SigningSecret, params))
+	return hmac.Equal(sig, expected)
+}
+
+func (s *Service) parseRenderParams(params RenderParams) (resizer.Preset, resizer.Format, error) {
+	width, err := parseDimension(params.Width, s.RenderCfg.MaxWidth)
+	if err != nil {
+		return resizer.Preset{}, "", fmt.Errorf("invalid w: %w", err)
+	}
+
+	height, err := parseDimension(params.Height, s.RenderCfg.MaxHeight)
+	if err != nil {
+		return resizer.Preset{}, "", fmt.Errorf("invalid h: %w", err)
+	}
+
+	if width == 0 && height == 0 {
+		return resizer.Preset{}, "", errors.New("w or h is required")
+	}
+
+	fit, err := resizer.ParseFit(params.Fit)
+	if err != nil {
+		return resizer.Preset{}, "", err
+	}
+
+	format, err := resizer.ParseFormat(params.Format)
+	if err != nil {
+		return resizer.Preset{}, "", err
+	}
+
+	preset := resizer.Preset{
+		Name:   "render",
+		Width:  width,
+		Height: height,
+		Fit:    fit,
+		Crop:   resizer.CropCenter,
+	}
+
+	return preset, format, nil
+}
+
+func parseDimension(value string, max uint) (uint, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	dimension, err := strconv.ParseUint(value, 10, 32)
+	if err != nil {
+		return 0, err
+	}
+	if uint(dimension) > max {
+		return 0, fmt.Errorf("must not exceed %d", max)
+	}
+
+	return uint(dimension), nil
+}
@@ -0,0 +1,454 @@
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/menyasosali/mts/internal/domain"
+	"github.com/menyasosali/mts/internal/service/db"
+	"github.com/menyasosali/mts/internal/service/minio"
+	"hash"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Возобновляемая загрузка по протоколу tus 1.0 (https://tus.io/protocols/resumable-upload),
+// расширения creation и termination. Каждая загрузка - multipart-загрузка MinIO, состояние
+// хранится в таблице uploads вместе с состоянием sha256 принятых байт. Завершенная загрузка
+// регистрируется так же, как в UploadImageHandler
+
+const (
+	_tusVersion    = "1.0.0"
+	_tusExtensions = "creation,termination"
+	_tusBasePath   = "/uploads/"
+	// _tusPartSize - по столько байт PATCH отправляет части в MinIO, это же максимум буфера на запрос
+	_tusPartSize = minio.MinPartSize
+)
+
+// TusOptionsHandler отдает возможности сервера
+func (s *Service) TusOptionsHandler(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	w.Header().Set("Tus-Resumable", _tusVersion)
+	w.Header().Set("Tus-Version", _tusVersion)
+	w.Header().Set("Tus-Extension", _tusExtensions)
+	w.Header().Set("Tus-Max-Size", strconv.FormatInt(s.UploadCfg.MaxSize, 10))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// TusCreateHandler создает загрузку длиной Upload-Length. Имя файла и тип берутся из Upload-Metadata
+// (ключи filename и filetype)
+func (s *Service) TusCreateHandler(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	if !checkTusVersion(w, r) {
+		return
+	}
+
+	length, err := strconv.ParseInt(r.Header.Get("Upload-Length"), 10, 64)
+	if err != nil || length <= 0 {
+		http.Error(w, "Invalid Upload-Length", http.StatusBadRequest)
+		return
+	}
+	if length > s.UploadCfg.MaxSize {
+		http.Error(w, fmt.Sprintf("Upload exceeds %d bytes", s.UploadCfg.MaxSize), http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	rawMetadata := r.Header.Get("Upload-Metadata")
+	metadata, err := parseUploadMetadata(rawMetadata)
+	if err != nil {
+		http.Error(w, "Invalid Upload-Metadata", http.StatusBadRequest)
+		return
+	}
+
+	upload := domain.Upload{
+		Filename:    metadata["filename"],
+		ContentType: metadata["filetype"],
+		Metadata:    rawMetadata,
+		Length:      length,
+	}
+	if upload.Filename == "" {
+		http.Error(w, "Upload-Metadata must contain filename", http.StatusBadRequest)
+		return
+	}
+	upload.Key = tmpOriginalKey(uuid.New().String())
+
+	upload.MultipartID, err = s.FileStorer.StartUpload(r.Context(), upload.Key, upload.ContentType)
+	if err != nil {
+		s.Logger.Error("Failed to start upload", err)
+		http.Error(w, "Failed to start upload", http.StatusInternalServerError)
+		return
+	}
+
+	upload.ID, err = s.Store.CreateUpload(r.Context(), upload)
+	if err != nil {
+		s.Logger.Error("Failed to save upload to db", err)
+		errAbort := s.FileStorer.AbortUpload(r.Context(), upload.Key, upload.MultipartID)
+		if errAbort != nil {
+			s.Logger.Error("Failed to abort upload", errAbort)
+		}
+		http.Error(w, "Failed to save upload to db", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Tus-Resumable", _tusVersion)
+	w.Header().Set("Location", _tusBasePath+upload.ID)
+	w.WriteHeader(http.StatusCreated)
+}
+
+// TusHeadHandler отдает текущий offset загрузки
+func (s *Service) TusHeadHandler(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+	if !checkTusVersion(w, r) {
+		return
+	}
+
+	upload, ok := s.getUpload(w, r, pathParams["id"])
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Tus-Resumable", _tusVersion)
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Upload-Offset", strconv.FormatInt(upload.Offset, 10))
+	w.Header().Set("Upload-Length", strconv.FormatInt(upload.Length, 10))
+	if upload.Metadata != "" {
+		w.Header().Set("Upload-Metadata", upload.Metadata)
+	}
+	if upload.Completed() {
+		w.Header().Set("X-Image-ID", upload.ImageID)
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// TusPatchHandler принимает байты с Upload-Offset. Тело режется на части по _tusPartSize и сразу
+// уходит в MinIO; остаток меньше части сохраняется в базе и дописывается следующим PATCH.
+// Если запрос оборвался, принятые байты все равно сохраняются и клиент продолжает с нового offset
+func (s *Service) TusPatchHandler(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+	if !checkTusVersion(w, r) {
+		return
+	}
+	if r.Header.Get("Content-Type") != "application/offset+octet-stream" {
+		http.Error(w, "Content-Type must be application/offset+octet-stream", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	uploadID := pathParams["id"]
+	_, locked := s.uploadLocks.LoadOrStore(uploadID, struct{}{})
+	if locked {
+		http.Error(w, "Upload is in progress", http.StatusLocked)
+		return
+	}
+	defer s.uploadLocks.Delete(uploadID)
+
+	upload, ok := s.getUpload(w, r, uploadID)
+	if !ok {
+		return
+	}
+
+	offset, err := strconv.ParseInt(r.Header.Get("Upload-Offset"), 10, 64)
+	if err != nil || offset != upload.Offset || upload.Completed() {
+		w.Header().Set("Upload-Offset", strconv.FormatInt(upload.Offset, 10))
+		http.Error(w, "Upload-Offset does not match", http.StatusConflict)
+		return
+	}
+
+	remaining := upload.Length - upload.Offset
+	if r.ContentLength > remaining {
+		http.Error(w, "Request body exceeds Upload-Length", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	// принятые байты сохраняются и после обрыва соединения, когда контекст запроса уже отменен
+	ctx := context.WithoutCancel(requestContext(r))
+
+	// изображение регистрирует последний PATCH, callback url берется из его заголовков
+	_, err = s.callbackURL(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// все байты уже приняты, если прошлый PATCH упал на завершении загрузки
+	if remaining > 0 {
+		err = s.receiveUpload(ctx, upload, io.LimitReader(r.Body, remaining))
+	}
+	if err != nil {
+		s.Logger.Error(fmt.Sprintf("Failed to receive upload %s: %v", uploadID, err))
+		if errors.Is(err, db.ErrUploadConflict) {
+			http.Error(w, "Upload-Offset does not match", http.StatusConflict)
+			return
+		}
+		http.Error(w, "Failed to receive upload", http.StatusInternalServerError)
+		return
+	}
+
+	if upload.Offset == upload.Length {
+		err = s.completeUpload(ctx, upload)
+		if err != nil {
+			s.Logger.Error(fmt.Sprintf("Failed to complete upload %s: %v", uploadID, err))
+			http.Error(w, "Failed to complete upload", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("X-Image-ID", upload.ImageID)
+	}
+
+	w.Header().Set("Tus-Resumable", _tusVersion)
+	w.Header().Set("Upload-Offset", strconv.FormatInt(upload.Offset, 10))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// TusDeleteHandler прерывает загрузку и удаляет ее состояние
+func (s *Service) TusDeleteHandler(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+	if !checkTusVersion(w, r) {
+		return
+	}
+
+	upload, ok := s.getUpload(w, r, pathParams["id"])
+	if !ok {
+		return
+	}
+
+	if !upload.Completed() {
+		err := s.FileStorer.AbortUpload(r.Context(), upload.Key, upload.MultipartID)
+		if err != nil {
+			s.Logger.Error("Failed to abort upload", err)
+			http.Error(w, "Failed to abort upload", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	err := s.Store.DeleteUpload(r.Context(), upload.ID)
+	if err != nil && !errors.Is(err, db.ErrUploadNotFound) {
+		s.Logger.Error("Failed to delete upload from db", err)
+		http.Error(w, "Failed to delete upload", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Tus-Resumable", _tusVersion)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// receiveUpload дописывает body к загрузке, upload обновляется до сохраненного в базе состояния
+func (s *Service) receiveUpload(ctx context.Context, upload *domain.Upload, body io.Reader) error {
+	digest, err := restoreHash(upload.HashState)
+	if err != nil {
+		return err
+	}
+
+	buf := make([]byte, _tusPartSize)
+	filled := copy(buf, upload.Tail)
+	received := upload.Offset
+
+	for {
+		n, errRead := io.ReadFull(body, buf[filled:])
+		digest.Write(buf[filled : filled+n])
+		filled += n
+		received += int64(n)
+
+		last := received == upload.Length
+		if errRead == nil || last {
+			// часть заполнена или это конец файла - отправляем в MinIO
+			if filled > 0 {
+				err := s.FileStorer.UploadPart(ctx, upload.Key, upload.MultipartID, upload.PartCount+1,
+					bytes.NewReader(buf[:filled]), int64(filled))
+				if err != nil {
+					return err
+				}
+				upload.PartCount++
+			}
+			filled = 0
+		}
+
+		err := s.saveUploadProgress(ctx, upload, received, buf[:filled], digest)
+		if err != nil {
+			return err
+		}
+
+		if errRead != nil || last {
+			if errRead == io.EOF || errRead == io.ErrUnexpectedEOF || last {
+				return nil
+			}
+			return fmt.Errorf("failed to read request body: %w", errRead)
+		}
+	}
+}
+
+func (s *Service) saveUploadProgress(ctx context.Context, upload *domain.Upload, offset int64, tail []byte,
+	digest hash.Hash) error {
+	hashState, err := digest.(encoding.BinaryMarshaler).MarshalBinary()
+	if err != nil {
+		return fmt.Errorf("failed to save upload hash state: %w", err)
+	}
+
+	prevOffset := upload.Offset
+	next := *upload
+	next.Offset = offset
+	next.Tail = tail
+	next.HashState = hashState
+
+	err = s.Store.UpdateUploadProgress(ctx, upload.ID, prevOffset, next)
+	if err != nil {
+		return err
+	}
+
+	upload.Offset = next.Offset
+	upload.Tail = append(upload.Tail[:0], tail...)
+	upload.HashState = hashState
+	return nil
+}
+
+// restoreHash продолжает sha256 загрузки с сохраненного состояния
+func restoreHash(state []byte) (hash.Hash, error) {
+	h := sha256.New()
+	if len(state) == 0 {
+		return h, nil
+	}
+
+	err := h.(encoding.BinaryUnmarshaler).UnmarshalBinary(state)
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore upload hash state: %w", err)
+	}
+	return h, nil
+}
+
+// completeUpload собирает объект в MinIO и отправляет изображение в тот же pipeline, что и UploadImageHandler.
+// Шаги идемпотентны: ключ оригинала сохраняется до регистрации, и повторный PATCH после сбоя сразу
+// регистрирует изображение - multipart-загрузки и временного объекта к этому времени уже нет
+func (s *Service) completeUpload(ctx context.Context, upload *domain.Upload) error {
+	digest, err := restoreHash(upload.HashState)
+	if err != nil {
+		return err
+	}
+	sum := hex.EncodeToString(digest.Sum(nil))
+
+	var original domain.ImgDescriptor
+	if upload.OriginalKey == "" {
+		original, err = s.assembleUpload(ctx, upload, sum)
+	} else {
+		original, err = s.uploadedOriginal(ctx, upload.OriginalKey, sum)
+	}
+	if err != nil {
+		return err
+	}
+	original.Name = upload.Filename
+
+	response, err := s.registerImage(ctx, original)
+	if err != nil {
+		return err
+	}
+
+	upload.ImageID = response.ImageID
+	return s.Store.UpdateUploadProgress(ctx, upload.ID, upload.Offset, *upload)
+}
+
+// assembleUpload собирает multipart-загрузку, переносит объект в originals/ и запоминает его ключ
+func (s *Service) assembleUpload(ctx context.Context, upload *domain.Upload, sum string) (domain.ImgDescriptor, error) {
+	var original domain.ImgDescriptor
+
+	_, err := s.FileStorer.CompleteUpload(ctx, upload.Key, upload.MultipartID, upload.PartCount)
+	if err == nil {
+		original, err = s.promoteOriginal(ctx, upload.Key, sum)
+	} else {
+		// прошлый запрос упал после сборки, но до сохранения ключа: объект лежит во временном
+		// ключе или уже перенесен
+		original, err = s.recoverOriginal(ctx, upload.Key, sum, err)
+	}
+	if err != nil {
+		return domain.ImgDescriptor{}, err
+	}
+
+	err = s.Store.SetUploadOriginal(ctx, upload.ID, original.ObjectKey)
+	if err != nil {
+		return domain.ImgDescriptor{}, err
+	}
+	upload.OriginalKey = original.ObjectKey
+
+	// теперь на оригинал ссылается загрузка
+	err = s.settleOriginal(ctx, upload.Key, original.ObjectKey)
+	if err != nil {
+		return domain.ImgDescriptor{}, err
+	}
+
+	return original, nil
+}
+
+// recoverOriginal ищет уже собранный объект загрузки, иначе возвращает ошибку сборки errComplete
+func (s *Service) recoverOriginal(ctx context.Context, tmpKey, sum string, errComplete error,
+) (domain.ImgDescriptor, error) {
+	_, err := s.FileStorer.StatImage(ctx, tmpKey)
+	if err == nil {
+		return s.promoteOriginal(ctx, tmpKey, sum)
+	}
+
+	_, err = s.FileStorer.StatImage(ctx, originalKey(sum))
+	if err == nil {
+		return s.uploadedOriginal(ctx, originalKey(sum), sum)
+	}
+
+	return domain.ImgDescriptor{}, errComplete
+}
+
+// uploadedOriginal - оригинал, который уже перенесен в originals/ прошлым запросом
+func (s *Service) uploadedOriginal(ctx context.Context, key, sum string) (domain.ImgDescriptor, error) {
+	imgURL, err := s.FileStorer.ImageURL(ctx, key)
+	if err != nil {
+		return domain.ImgDescriptor{}, err
+	}
+
+	return domain.ImgDescriptor{URL: imgURL, ObjectKey: key, SHA256: sum}, nil
+}
+
+func (s *Service) getUpload(w http.ResponseWriter, r *http.Request, uploadID string) (*domain.Upload, bool) {
+	upload, err := s.Store.GetUpload(r.Context(), uploadID)
+	if err != nil {
+		if errors.Is(err, db.ErrUploadNotFound) {
+			http.Error(w, "Upload not found", http.StatusNotFound)
+			return nil, false
+		}
+		s.Logger.Error("Failed to get upload from db", err)
+		http.Error(w, "Failed to get upload", http.StatusInternalServerError)
+		return nil, false
+	}
+
+	return upload, true
+}
+
+func checkTusVersion(w http.ResponseWriter, r *http.Request) bool {
+	if r.Header.Get("Tus-Resumable") != _tusVersion {
+		w.Header().Set("Tus-Version", _tusVersion)
+		http.Error(w, "Unsupported Tus-Resumable version", http.StatusPreconditionFailed)
+		return false
+	}
+	return true
+}
+
+// parseUploadMetadata разбирает Upload-Metadata: пары "ключ base64(значение)" через запятую
+func parseUploadMetadata(header string) (map[string]string, error) {
+	metadata := make(map[string]string)
+	if strings.TrimSpace(header) == "" {
+		return metadata, nil
+	}
+
+	for _, pair := range strings.Split(header, ",") {
+		fields := strings.Fields(pair)
+		switch len(fields) {
+		case 1:
+			metadata[fields[0]] = ""
+		case 2:
+			value, err := base64.StdEncoding.DecodeString(fields[1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid metadata value for %q: %w", fields[0], err)
+			}
+			metadata[fields[0]] = string(value)
+		default:
+			return nil, fmt.Errorf("invalid metadata pair %q", pair)
+		}
+	}
+
+	return metadata, nil
+}
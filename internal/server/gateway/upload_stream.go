@@ -0,0 +1,174 @@
+package gateway
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	pb "github.com/menyasosali/mts/pkg/gen"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"io"
+	"strings"
+)
+
+var (
+	errUploadTooLarge    = errors.New("upload exceeds max size")
+	errMetadataDuplicate = errors.New("upload metadata must be sent only once")
+	errUploadSize        = errors.New("received size does not match declared size")
+)
+
+// UploadImage принимает файл потоком чанков: первый несет UploadMetadata, остальные - данные.
+// Данные сразу уходят в MinIO, размер и sha256 проверяются по окончании потока
+func (s *Service) UploadImage(stream pb.Gateway_UploadImageServer) error {
+	ctx := stream.Context()
+
+	first, err := stream.Recv()
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "failed to receive upload metadata: %v", err)
+	}
+	metadata := first.GetMetadata()
+	if metadata == nil {
+		return status.Error(codes.InvalidArgument, "first message must carry upload metadata")
+	}
+	if metadata.GetFilename() == "" {
+		return status.Error(codes.InvalidArgument, "filename is required")
+	}
+	if metadata.GetSize() > s.UploadCfg.MaxSize {
+		return status.Errorf(codes.InvalidArgument, "file exceeds %d bytes", s.UploadCfg.MaxSize)
+	}
+	if _, err := s.callbackURL(ctx); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	checksum := strings.ToLower(metadata.GetChecksum())
+	if checksum != "" {
+		if _, err := hex.DecodeString(checksum); err != nil || len(checksum) != sha256.Size*2 {
+			return status.Error(codes.InvalidArgument, "checksum must be a hex encoded sha256")
+		}
+	}
+
+	// с заявленным размером MinIO загружает объект одним запросом, без буферизации частей
+	size := int64(-1)
+	reader := &chunkReader{stream: stream, limit: s.UploadCfg.MaxSize}
+	if metadata.GetSize() > 0 {
+		size = metadata.GetSize()
+		reader.expected = size
+	}
+
+	staged, err := s.stageOriginal(ctx, reader, size, metadata.GetContentType())
+	if err == nil && size > 0 {
+		// MinIO читает ровно size байт, лишние данные в потоке остаются непрочитанными
+		err = reader.expectEOF()
+		if err != nil {
+			s.discardOriginal(ctx, staged.TmpKey)
+		}
+	}
+	if err != nil {
+		switch {
+		case errors.Is(reader.err, errUploadSize):
+			return status.Errorf(codes.DataLoss, "%v: expected %d bytes", reader.err, size)
+		case errors.Is(reader.err, errUploadTooLarge):
+			return status.Errorf(codes.InvalidArgument, "file exceeds %d bytes", s.UploadCfg.MaxSize)
+		case errors.Is(reader.err, errMetadataDuplicate):
+			return status.Error(codes.InvalidArgument, reader.err.Error())
+		case reader.err != nil:
+			// ошибка самого потока, уже со статусом gRPC
+			return reader.err
+		}
+		s.Logger.Error("Failed to upload image", err)
+		return status.Errorf(codes.Internal, "failed to upload image: %v", err)
+	}
+
+	var reason string
+	switch {
+	case metadata.GetSize() > 0 && staged.Size != metadata.GetSize():
+		reason = fmt.Sprintf("received %d bytes, expected %d", staged.Size, metadata.GetSize())
+	case checksum != "" && staged.SHA256 != checksum:
+		reason = "checksum mismatch"
+	}
+	if reason != "" {
+		s.discardOriginal(ctx, staged.TmpKey)
+		return status.Error(codes.DataLoss, reason)
+	}
+
+	original, err := s.promoteOriginal(ctx, staged.TmpKey, staged.SHA256)
+	if err != nil {
+		s.Logger.Error("Failed to store original", err)
+		return status.Errorf(codes.Internal, "failed to store original: %v", err)
+	}
+	original.Name = metadata.GetFilename()
+
+	response, err := s.registerOriginal(ctx, staged.TmpKey, original)
+	if err != nil {
+		s.Logger.Error("Failed to register image", err)
+		return status.Errorf(codes.Internal, "failed to register image: %v", err)
+	}
+
+	return stream.SendAndClose(&pb.UploadImageResponse{
+		ImageID:      response.ImageID,
+		Name:         response.Name,
+		OriginalURL:  response.OriginalURL,
+		Deduplicated: response.Deduplicated,
+	})
+}
+
+// chunkReader читает данные из потока UploadChunk и ограничивает размер. Если задан expected,
+// поток должен содержать ровно столько байт. Ошибка потока сохраняется в err, так как MinIO
+// возвращает ее уже обернутой
+type chunkReader struct {
+	stream   pb.Gateway_UploadImageServer
+	limit    int64
+	expected int64
+	size     int64
+	buf      []byte
+	err      error
+}
+
+// expectEOF проверяет, что после прочитанных данных поток закончился
+func (r *chunkReader) expectEOF() error {
+	n, err := r.Read(make([]byte, 1))
+	if err == io.EOF {
+		return nil
+	}
+	if n > 0 {
+		r.err = errUploadSize
+		return r.err
+	}
+	return err
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		chunk, err := r.stream.Recv()
+		if err == io.EOF {
+			if r.expected > 0 && r.size != r.expected {
+				r.err = errUploadSize
+				return 0, r.err
+			}
+			return 0, io.EOF
+		}
+		if err != nil {
+			r.err = err
+			return 0, err
+		}
+		if chunk.GetMetadata() != nil {
+			r.err = errMetadataDuplicate
+			return 0, r.err
+		}
+		r.buf = chunk.GetData()
+	}
+
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	r.size += int64(n)
+	switch {
+	case r.expected > 0 && r.size > r.expected:
+		r.err = errUploadSize
+		return 0, r.err
+	case r.size > r.limit:
+		r.err = errUploadTooLarge
+		return 0, r.err
+	}
+
+	return n, nil
+}
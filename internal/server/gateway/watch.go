@@ -0,0 +1,260 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/menyasosali/mts/internal/domain"
+	"github.com/menyasosali/mts/internal/service/db"
+	pb "github.com/menyasosali/mts/pkg/gen"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const _sseKeepAlive = 15 * time.Second
+
+// ImageEvents - подписка на события изображений (events.Hub)
+type ImageEvents interface {
+	Subscribe(imageID string) (<-chan domain.ImageEvent, func())
+}
+
+var errImageGone = errors.New("image is deleted")
+
+// WatchImage стримит смены статуса и готовые превью изображения до ready, failed или deleted
+func (s *Service) WatchImage(req *pb.WatchImageRequest, stream pb.Gateway_WatchImageServer) error {
+	imageID := req.GetId()
+	if imageID == "" {
+		return status.Error(codes.InvalidArgument, "image ID is required")
+	}
+
+	err := s.watchImage(stream.Context(), imageID, func(event *pb.ImageEvent) error {
+		return stream.Send(event)
+	})
+	if err != nil {
+		return watchError(imageID, err)
+	}
+
+	return nil
+}
+
+// WatchImageHandler - WatchImage в виде text/event-stream: события status и variant с JSON
+// ImageEvent в data
+func (s *Service) WatchImageHandler(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+	imageID := pathParams["id"]
+	controller := http.NewResponseController(w)
+
+	// поток живет дольше WriteTimeout сервера
+	err := controller.SetWriteDeadline(time.Time{})
+	if err != nil {
+		s.Logger.Warn(fmt.Sprintf("Failed to disable write deadline for image %s events: %v", imageID, err))
+	}
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	var mu sync.Mutex
+	started := false
+	write := func(format string, args ...interface{}) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if !started {
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.Header().Set("Cache-Control", "no-cache")
+			w.Header().Set("X-Accel-Buffering", "no")
+			w.WriteHeader(http.StatusOK)
+			started = true
+		}
+
+		_, err := fmt.Fprintf(w, format, args...)
+		if err != nil {
+			return err
+		}
+		return controller.Flush()
+	}
+
+	go func() {
+		ticker := time.NewTicker(_sseKeepAlive)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if write(": keep-alive\n\n") != nil {
+					cancel()
+					return
+				}
+			}
+		}
+	}()
+
+	err = s.watchImage(ctx, imageID, func(event *pb.ImageEvent) error {
+		name := "variant"
+		if event.GetStatus() != nil {
+			name = "status"
+		}
+
+		data, err := protojson.Marshal(event)
+		if err != nil {
+			return err
+		}
+
+		return write("event: %s\ndata: %s\n\n", name, data)
+	})
+	if err == nil || ctx.Err() != nil {
+		return
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if started {
+		// заголовки уже отправлены, клиент увидит обрыв потока
+		s.Logger.Error(fmt.Sprintf("Image %s events stream failed: %v", imageID, err))
+		return
+	}
+
+	st := status.Convert(watchError(imageID, err))
+	switch st.Code() {
+	case codes.NotFound:
+		http.Error(w, st.Message(), http.StatusNotFound)
+	default:
+		http.Error(w, st.Message(), http.StatusInternalServerError)
+	}
+}
+
+func watchError(imageID string, err error) error {
+	if errors.Is(err, db.ErrImageNotFound) {
+		return status.Errorf(codes.NotFound, "image %s not found", imageID)
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	return status.Errorf(codes.Internal, "failed to watch image: %v", err)
+}
+
+// imageWatch - что уже отправлено наблюдателю
+type imageWatch struct {
+	imageID  string
+	send     func(*pb.ImageEvent) error
+	status   domain.ImageStatus
+	variants map[string]bool // preset/format
+}
+
+// watchImage подписывается на события и отправляет текущее состояние из базы, затем события по
+// мере поступления. Если подписку закрыл хаб (отставание или переподключение к базе), события
+// могли потеряться - состояние перечитывается и подписка повторяется
+func (s *Service) watchImage(ctx context.Context, imageID string, send func(*pb.ImageEvent) error) error {
+	watch := &imageWatch{imageID: imageID, send: send, variants: make(map[string]bool)}
+
+	for first := true; ; first = false {
+		events, unsubscribe := s.Events.Subscribe(imageID)
+
+		done, err := s.sendImageState(ctx, watch, first)
+		if err != nil || done {
+			unsubscribe()
+			return err
+		}
+
+		done, err = s.forwardImageEvents(ctx, watch, events)
+		unsubscribe()
+		if err != nil || done {
+			return err
+		}
+	}
+}
+
+// sendImageState отправляет статус и превью из базы, которых наблюдатель еще не видел, и
+// сообщает, завершено ли наблюдение
+func (s *Service) sendImageState(ctx context.Context, watch *imageWatch, first bool) (bool, error) {
+	img, err := s.Store.GetImageByID(ctx, watch.imageID)
+	if err != nil {
+		if errors.Is(err, db.ErrImageNotFound) && !first {
+			// удалено, пока шло наблюдение
+			return true, watch.sendStatus(domain.ImageEvent{Status: domain.StatusDeleted})
+		}
+		return false, err
+	}
+
+	for _, variant := range img.Variants {
+		err = watch.sendVariant(variant)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	err = watch.sendStatus(domain.ImageEvent{Status: img.Status, FailureReason: img.FailureReason})
+	if err != nil {
+		return false, err
+	}
+
+	return terminalStatus(img.Status), nil
+}
+
+func (s *Service) forwardImageEvents(ctx context.Context, watch *imageWatch, events <-chan domain.ImageEvent,
+) (bool, error) {
+	for {
+		select {
+		case <-ctx.Done():
+			return true, nil
+		case event, ok := <-events:
+			if !ok {
+				return false, nil
+			}
+
+			switch {
+			case event.Variant != nil:
+				err := watch.sendVariant(*event.Variant)
+				if err != nil {
+					return false, err
+				}
+			case event.Status == domain.StatusReady:
+				// превью сохраняются вместе со статусом ready: отправляем те, уведомления о которых
+				// не дошли, и только потом сам статус
+				return s.sendImageState(ctx, watch, false)
+			default:
+				err := watch.sendStatus(event)
+				if err != nil || terminalStatus(event.Status) {
+					return true, err
+				}
+			}
+		}
+	}
+}
+
+func (w *imageWatch) sendStatus(event domain.ImageEvent) error {
+	if event.Status == w.status {
+		return nil
+	}
+	w.status = event.Status
+
+	return w.send(&pb.ImageEvent{
+		ImageID: w.imageID,
+		Event: &pb.ImageEvent_Status{Status: &pb.ImageStatusEvent{
+			Status:        imageStatusToPb(event.Status),
+			FailureReason: event.FailureReason,
+		}},
+	})
+}
+
+func (w *imageWatch) sendVariant(variant domain.Variant) error {
+	key := variant.Preset + "/" + variant.Format
+	if w.variants[key] {
+		return nil
+	}
+	w.variants[key] = true
+
+	return w.send(&pb.ImageEvent{
+		ImageID: w.imageID,
+		Event:   &pb.ImageEvent_Variant{Variant: variantsToPb([]domain.Variant{variant})[0]},
+	})
+}
+
+func terminalStatus(status domain.ImageStatus) bool {
+	return status == domain.StatusReady || status == domain.StatusFailed || status == domain.StatusDeleted
+}
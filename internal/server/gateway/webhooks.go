@@ -0,0 +1,89 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"github.com/menyasosali/mts/internal/domain"
+	"github.com/menyasosali/mts/internal/service/db"
+	pb "github.com/menyasosali/mts/pkg/gen"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ListWebhookDeliveries отдает журнал webhook изображения: доставки и каждую попытку с кодом ответа
+func (s *Service) ListWebhookDeliveries(ctx context.Context, req *pb.ListWebhookDeliveriesRequest,
+) (*pb.ListWebhookDeliveriesResponse, error) {
+	imageID := req.GetId()
+	if imageID == "" {
+		return nil, status.Error(codes.InvalidArgument, "image ID is required")
+	}
+
+	_, err := s.Store.GetImageByID(ctx, imageID)
+	if err != nil {
+		if errors.Is(err, db.ErrImageNotFound) {
+			return nil, status.Errorf(codes.NotFound, "image %s not found", imageID)
+		}
+		s.Logger.Error("Failed to get image from db", err)
+		return nil, status.Errorf(codes.Internal, "failed to get image from db: %v", err)
+	}
+
+	deliveries, err := s.Store.ListWebhookDeliveries(ctx, imageID)
+	if err != nil {
+		s.Logger.Error("Failed to get webhook deliveries from db", err)
+		return nil, status.Errorf(codes.Internal, "failed to get webhook deliveries: %v", err)
+	}
+
+	response := &pb.ListWebhookDeliveriesResponse{
+		Deliveries: make([]*pb.WebhookDelivery, 0, len(deliveries)),
+	}
+	for _, delivery := range deliveries {
+		response.Deliveries = append(response.Deliveries, webhookDeliveryToPb(delivery))
+	}
+
+	return response, nil
+}
+
+func webhookDeliveryToPb(delivery domain.WebhookDelivery) *pb.WebhookDelivery {
+	result := &pb.WebhookDelivery{
+		DeliveryID:     delivery.ID,
+		URL:            delivery.URL,
+		Event:          delivery.Event,
+		Status:         webhookStatusToPb(delivery.Status),
+		Attempts:       int32(delivery.Attempts),
+		LastStatusCode: int32(delivery.LastStatusCode),
+		LastError:      delivery.LastError,
+		CreatedAt:      timestamppb.New(delivery.CreatedAt),
+	}
+	if delivery.Status == domain.WebhookPending {
+		result.NextAttemptAt = timestamppb.New(delivery.NextAttemptAt)
+	}
+	if delivery.DeliveredAt != nil {
+		result.DeliveredAt = timestamppb.New(*delivery.DeliveredAt)
+	}
+
+	for _, attempt := range delivery.AttemptLog {
+		result.AttemptLog = append(result.AttemptLog, &pb.WebhookAttempt{
+			Attempt:    int32(attempt.Attempt),
+			StatusCode: int32(attempt.StatusCode),
+			Error:      attempt.Error,
+			DurationMs: attempt.Duration.Milliseconds(),
+			CreatedAt:  timestamppb.New(attempt.CreatedAt),
+		})
+	}
+
+	return result
+}
+
+func webhookStatusToPb(status domain.WebhookStatus) pb.WebhookStatus {
+	switch status {
+	case domain.WebhookPending:
+		return pb.WebhookStatus_WEBHOOK_STATUS_PENDING
+	case domain.WebhookDelivered:
+		return pb.WebhookStatus_WEBHOOK_STATUS_DELIVERED
+	case domain.WebhookFailed:
+		return pb.WebhookStatus_WEBHOOK_STATUS_FAILED
+	default:
+		return pb.WebhookStatus_WEBHOOK_STATUS_UNSPECIFIED
+	}
+}
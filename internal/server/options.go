@@ -13,6 +13,12 @@ func Port(port string) Option {
 	}
 }
 
+func GRPCPort(port string) Option {
+	return func(s *Server) {
+		s.grpcAddr = net.JoinHostPort("", port)
+	}
+}
+
 func ReadTimeout(timeout time.Duration) Option {
 	return func(s *Server) {
 		s.server.ReadTimeout = timeout
@@ -2,15 +2,15 @@ package server
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"github.com/menyasosali/mts/internal/server/gateway"
 	pb "github.com/menyasosali/mts/pkg/gen"
-	"golang.org/x/net/http2"
-	"golang.org/x/net/http2/h2c"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
+	"net"
 	"net/http"
-	"strings"
 	"time"
 
 	"github.com/menyasosali/mts/pkg/logger"
@@ -20,21 +20,38 @@ const (
 	_defaultReadTimeout     = 5 * time.Second
 	_defaultWriteTimeout    = 5 * time.Second
 	_defaultAddr            = ":8080"
+	_defaultGRPCAddr        = ":9090"
 	_defaultShutdownTimeout = 3 * time.Second
 )
 
+// Server - REST (grpc-gateway) и HTTP-обработчики на HTTP-порту и gRPC на отдельном. gRPC не
+// проходит через http.Server: его ReadTimeout и WriteTimeout обрывали бы потоки WatchImage и UploadImage
 type Server struct {
 	server          *http.Server
+	grpcServer      *grpc.Server
+	grpcAddr        string
+	grpcListener    net.Listener
 	notify          chan error
 	shutdownTimeout time.Duration
 }
 
-func NewServer(ctx context.Context, log logger.Interface, service *gateway.Service, opts ...Option) *Server {
+func NewServer(ctx context.Context, log logger.Interface, service *gateway.Service, opts ...Option,
+) (*Server, error) {
 	grpcServer := grpc.NewServer()
 	grpcOpts := []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
 	gwmux := runtime.NewServeMux()
 	pb.RegisterGatewayServer(grpcServer, service)
 	gwmux.HandlePath("POST", "/images/upload", service.UploadImageHandler)
+	gwmux.HandlePath("GET", "/images/{id}/{preset}", service.GetVariantHandler)
+	// регистрируются после {preset}: mux проверяет последние зарегистрированные пути первыми
+	gwmux.HandlePath("GET", "/images/{id}/render", service.RenderHandler)
+	gwmux.HandlePath("GET", "/images/{id}/events", service.WatchImageHandler)
+	// tus: возобновляемая загрузка
+	gwmux.HandlePath("OPTIONS", "/uploads", service.TusOptionsHandler)
+	gwmux.HandlePath("POST", "/uploads", service.TusCreateHandler)
+	gwmux.HandlePath("HEAD", "/uploads/{id}", service.TusHeadHandler)
+	gwmux.HandlePath("PATCH", "/uploads/{id}", service.TusPatchHandler)
+	gwmux.HandlePath("DELETE", "/uploads/{id}", service.TusDeleteHandler)
 
 	mux := http.NewServeMux()
 	mux.Handle("/", gwmux)
@@ -43,12 +60,14 @@ func NewServer(ctx context.Context, log logger.Interface, service *gateway.Servi
 		ReadTimeout:  _defaultReadTimeout,
 		WriteTimeout: _defaultWriteTimeout,
 		Addr:         _defaultAddr,
-		Handler:      grpcHandlerFunc(grpcServer, mux),
+		Handler:      mux,
 	}
 
 	s := &Server{
 		server:          httpServer,
-		notify:          make(chan error, 1),
+		grpcServer:      grpcServer,
+		grpcAddr:        _defaultGRPCAddr,
+		notify:          make(chan error, 2),
 		shutdownTimeout: _defaultShutdownTimeout,
 	}
 
@@ -56,14 +75,33 @@ func NewServer(ctx context.Context, log logger.Interface, service *gateway.Servi
 		opt(s)
 	}
 
-	pb.RegisterGatewayHandlerFromEndpoint(ctx, gwmux, httpServer.Addr, grpcOpts)
+	listener, err := net.Listen("tcp", s.grpcAddr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen gRPC on %s: %w", s.grpcAddr, err)
+	}
+	s.grpcListener = listener
+
+	// REST проксируется в gRPC этого же процесса
+	err = pb.RegisterGatewayHandlerFromEndpoint(ctx, gwmux, listener.Addr().String(), grpcOpts)
+	if err != nil {
+		listener.Close()
+		return nil, fmt.Errorf("failed to register gateway handler: %w", err)
+	}
 
 	s.start(ctx, log)
 
-	return s
+	return s, nil
 }
 
 func (s *Server) start(ctx context.Context, logger logger.Interface) {
+	go func() {
+		err := s.grpcServer.Serve(s.grpcListener)
+		if err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+			logger.Error("gRPC server error", err)
+			s.notify <- err
+		}
+	}()
+
 	go func() {
 		err := s.server.ListenAndServe()
 		if err != nil && err != http.ErrServerClosed {
@@ -77,10 +115,7 @@ func (s *Server) start(ctx context.Context, logger logger.Interface) {
 		select {
 		case <-ctx.Done():
 			logger.Info("Shutting down HTTP server...")
-			ctx, cancel := context.WithTimeout(context.Background(), s.shutdownTimeout)
-			defer cancel()
-
-			err := s.server.Shutdown(ctx)
+			err := s.Shutdown()
 			if err != nil {
 				logger.Error("HTTP server shutdown error", err)
 				s.notify <- err
@@ -100,15 +135,20 @@ func (s *Server) Shutdown() error {
 	ctx, cancel := context.WithTimeout(context.Background(), s.shutdownTimeout)
 	defer cancel()
 
-	return s.server.Shutdown(ctx)
-}
+	// открытые потоки WatchImage не дают GracefulStop завершиться, по таймауту они обрываются
+	stopped := make(chan struct{})
+	go func() {
+		s.grpcServer.GracefulStop()
+		close(stopped)
+	}()
 
-func grpcHandlerFunc(grpcServer *grpc.Server, otherHandler http.Handler) http.Handler {
-	return h2c.NewHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if r.ProtoMajor == 2 && strings.Contains(r.Header.Get("Content-Type"), "application/grpc") {
-			grpcServer.ServeHTTP(w, r)
-		} else {
-			otherHandler.ServeHTTP(w, r)
-		}
-	}), &http2.Server{})
+	err := s.server.Shutdown(ctx)
+
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		s.grpcServer.Stop()
+	}
+
+	return err
 }
@@ -0,0 +1,77 @@
+package server
+
+import (
+	"context"
+	"github.com/menyasosali/mts/config"
+	"github.com/menyasosali/mts/internal/domain"
+	"github.com/menyasosali/mts/internal/server/gateway"
+	"github.com/menyasosali/mts/internal/service/db"
+	pb "github.com/menyasosali/mts/pkg/gen"
+	"github.com/menyasosali/mts/pkg/logger"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"testing"
+	"time"
+)
+
+type processingStore struct {
+	db.StoreInterface
+}
+
+func (processingStore) GetImageByID(_ context.Context, imageID string) (*domain.ImgDescriptor, error) {
+	return &domain.ImgDescriptor{ID: imageID, Status: domain.StatusProcessing}, nil
+}
+
+type channelEvents chan domain.ImageEvent
+
+func (e channelEvents) Subscribe(string) (<-chan domain.ImageEvent, func()) {
+	return e, func() {}
+}
+
+func TestWatchImageOutlivesHTTPTimeouts(t *testing.T) {
+	const timeout = 200 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	l := logger.NewLogger("error")
+	events := make(channelEvents, 1)
+	service := gateway.NewService(l, nil, processingStore{}, events, "", config.RenderConfig{},
+		config.UploadConfig{}, config.WebhookConfig{})
+
+	s, err := NewServer(ctx, l, service, Port("0"), GRPCPort("0"), ReadTimeout(timeout), WriteTimeout(timeout))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Shutdown()
+
+	conn, err := grpc.Dial(s.grpcListener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	stream, err := pb.NewGatewayClient(conn).WatchImage(ctx, &pb.WatchImageRequest{Id: "image-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	event, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("first event: %v", err)
+	}
+	if event.GetStatus().GetStatus() != pb.ImageStatus_IMAGE_STATUS_PROCESSING {
+		t.Fatalf("got %v, want processing", event)
+	}
+
+	// worker думает дольше таймаутов HTTP-сервера
+	time.Sleep(3 * timeout)
+	events <- domain.ImageEvent{ImageID: "image-1", Status: domain.StatusFailed, FailureReason: "broken"}
+
+	event, err = stream.Recv()
+	if err != nil {
+		t.Fatalf("watch was cut after %s: %v", 3*timeout, err)
+	}
+	if event.GetStatus().GetStatus() != pb.ImageStatus_IMAGE_STATUS_FAILED {
+		t.Fatalf("got %v, want failed", event)
+	}
+}
@@ -0,0 +1,120 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/jackc/pgx/v4"
+	"github.com/menyasosali/mts/internal/domain"
+)
+
+// ImageEventsChannel - канал LISTEN/NOTIFY с событиями изображений. Смены статуса отправляет
+// триггер из 012_image_events, готовые превью - worker через NotifyVariant. Уведомления не хранятся:
+// кто не слушал канал в момент отправки, перечитывает состояние из images
+const ImageEventsChannel = "image_events"
+
+// imageEventPayload - JSON уведомления, поля статуса совпадают с json_build_object в триггере
+type imageEventPayload struct {
+	ImageID       string          `json:"image_id"`
+	Status        string          `json:"status,omitempty"`
+	FailureReason string          `json:"failure_reason,omitempty"`
+	Variant       *variantPayload `json:"variant,omitempty"`
+}
+
+type variantPayload struct {
+	Preset      string `json:"preset"`
+	Format      string `json:"format"`
+	ContentType string `json:"content_type"`
+	Key         string `json:"object_key"`
+	URL         string `json:"url"`
+	Width       int    `json:"width"`
+	Height      int    `json:"height"`
+}
+
+// NotifyVariant сообщает слушателям ImageEventsChannel о готовом превью. В базу превью попадает
+// позже, вместе с остальными, в UpdateImage
+func (s *Store) NotifyVariant(ctx context.Context, imageID string, variant domain.Variant) error {
+	payload, err := json.Marshal(imageEventPayload{
+		ImageID: imageID,
+		Variant: &variantPayload{
+			Preset:      variant.Preset,
+			Format:      variant.Format,
+			ContentType: variant.ContentType,
+			Key:         variant.Key,
+			URL:         variant.URL,
+			Width:       variant.Width,
+			Height:      variant.Height,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal variant event: %w", err)
+	}
+
+	_, err = s.Pg.Pool.Exec(ctx, `SELECT pg_notify($1, $2)`, ImageEventsChannel, string(payload))
+	if err != nil {
+		return fmt.Errorf("failed to notify variant event: %w", err)
+	}
+
+	return nil
+}
+
+// ListenImageEvents слушает ImageEventsChannel на отдельном соединении (не из пула) и передает
+// события в handle, пока не отменен ctx или не оборвалось соединение. listening вызывается, как
+// только LISTEN выполнен: с этого момента уведомления больше не теряются
+func (s *Store) ListenImageEvents(ctx context.Context, listening func(), handle func(domain.ImageEvent)) error {
+	conn, err := pgx.ConnectConfig(ctx, s.Pg.Pool.Config().ConnConfig)
+	if err != nil {
+		return fmt.Errorf("failed to connect for image events: %w", err)
+	}
+	defer conn.Close(context.Background())
+
+	_, err = conn.Exec(ctx, "LISTEN "+ImageEventsChannel)
+	if err != nil {
+		return fmt.Errorf("failed to listen for image events: %w", err)
+	}
+	listening()
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to wait for image event: %w", err)
+		}
+
+		event, err := decodeImageEvent(notification.Payload)
+		if err != nil {
+			s.Logger.Warn(fmt.Sprintf("Skip malformed image event %q: %v", notification.Payload, err))
+			continue
+		}
+		handle(event)
+	}
+}
+
+func decodeImageEvent(payload string) (domain.ImageEvent, error) {
+	var p imageEventPayload
+	err := json.Unmarshal([]byte(payload), &p)
+	if err != nil {
+		return domain.ImageEvent{}, err
+	}
+	if p.ImageID == "" {
+		return domain.ImageEvent{}, fmt.Errorf("image_id is required")
+	}
+
+	event := domain.ImageEvent{
+		ImageID:       p.ImageID,
+		Status:        domain.ImageStatus(p.Status),
+		FailureReason: p.FailureReason,
+	}
+	if p.Variant != nil {
+		event.Variant = &domain.Variant{
+			Preset:      p.Variant.Preset,
+			Format:      p.Variant.Format,
+			ContentType: p.Variant.ContentType,
+			Key:         p.Variant.Key,
+			URL:         p.Variant.URL,
+			Width:       p.Variant.Width,
+			Height:      p.Variant.Height,
+		}
+	}
+
+	return event, nil
+}
@@ -0,0 +1,190 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"github.com/jackc/pgx/v4"
+	"github.com/menyasosali/mts/internal/domain"
+	"github.com/menyasosali/mts/internal/service/outbox"
+	"time"
+)
+
+// querier - пул или транзакция
+type querier interface {
+	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+}
+
+// ReferencedObjects возвращает те ключи из keys, на которые ссылается база: оригиналы, превью,
+// незавершенные и собранные, но не зарегистрированные tus-загрузки и кэш рендера существующих изображений (render/<image_id>/...)
+func (s *Store) ReferencedObjects(ctx context.Context, keys []string) (map[string]bool, error) {
+	return s.referencedObjects(ctx, s.Pg.Pool, keys)
+}
+
+func (s *Store) referencedObjects(ctx context.Context, q querier, keys []string) (map[string]bool, error) {
+	query := `
+		SELECT k
+		FROM unnest($1::text[]) AS k
+		WHERE EXISTS (SELECT 1 FROM images WHERE object_key = k)
+			OR EXISTS (SELECT 1 FROM image_variants WHERE object_key = k)
+			OR EXISTS (SELECT 1 FROM uploads WHERE object_key = k)
+			OR EXISTS (SELECT 1 FROM uploads WHERE original_key = k)
+			OR (k LIKE 'render/%' AND EXISTS (SELECT 1 FROM images WHERE image_id = split_part(k, '/', 2)))
+	`
+
+	rows, err := q.Query(ctx, query, keys)
+	if err != nil {
+		s.Logger.Error(fmt.Sprintf("Failed to get referenced objects from database: %v", err))
+		return nil, fmt.Errorf("failed to get referenced objects from database: %w", err)
+	}
+	defer rows.Close()
+
+	referenced := make(map[string]bool, len(keys))
+	for rows.Next() {
+		var key string
+		err = rows.Scan(&key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan referenced object: %w", err)
+		}
+		referenced[key] = true
+	}
+
+	return referenced, rows.Err()
+}
+
+// LockObject выполняет fn под advisory-блокировкой ключа объекта. Оригиналы общие для всех
+// изображений с тем же sha256, поэтому проверка ссылок и удаление (DeleteUnreferencedObject) не должны
+// пересекаться с проверкой оригинала только что зарегистрированной загрузкой. fn не должна ходить
+// в базу: соединение блокировки занято до ее завершения
+func (s *Store) LockObject(ctx context.Context, key string, fn func(context.Context) error) error {
+	tx, err := s.Pg.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext($1))`, key)
+	if err != nil {
+		s.Logger.Error(fmt.Sprintf("Failed to lock object %s: %v", key, err))
+		return fmt.Errorf("failed to lock object %s: %w", key, err)
+	}
+
+	err = fn(ctx)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// DeleteUnreferencedObject удаляет объект через remove, если на key не ссылается база. Проверка и
+// удаление идут под блокировкой LockObject. Возвращает false, если ссылка появилась
+func (s *Store) DeleteUnreferencedObject(ctx context.Context, key string, remove func(context.Context) error,
+) (bool, error) {
+	tx, err := s.Pg.Pool.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext($1))`, key)
+	if err != nil {
+		s.Logger.Error(fmt.Sprintf("Failed to lock object %s: %v", key, err))
+		return false, fmt.Errorf("failed to lock object %s: %w", key, err)
+	}
+
+	referenced, err := s.referencedObjects(ctx, tx, []string{key})
+	if err != nil {
+		return false, err
+	}
+	if referenced[key] {
+		return false, nil
+	}
+
+	err = remove(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit(ctx)
+}
+
+// StuckImages возвращает до limit изображений, которые дольше olderThan не выходят из uploaded,
+// queued или processing
+func (s *Store) StuckImages(ctx context.Context, olderThan time.Duration, limit int) ([]domain.ImgDescriptor, error) {
+	statuses := []string{string(domain.StatusUploaded), string(domain.StatusQueued), string(domain.StatusProcessing)}
+	return s.staleImages(ctx, statuses, olderThan, limit)
+}
+
+// DeletedImages возвращает до limit надгробий старше olderThan - их удаление не дошло до конца
+func (s *Store) DeletedImages(ctx context.Context, olderThan time.Duration, limit int) ([]domain.ImgDescriptor, error) {
+	return s.staleImages(ctx, []string{string(domain.StatusDeleted)}, olderThan, limit)
+}
+
+func (s *Store) staleImages(ctx context.Context, statuses []string, olderThan time.Duration, limit int,
+) ([]domain.ImgDescriptor, error) {
+	query := `
+		SELECT image_id, name, original_url, object_key, COALESCE(sha256, ''), status, created_at, updated_at
+		FROM images
+		WHERE status = ANY($1) AND updated_at < now() - $2 * interval '1 millisecond'
+		ORDER BY updated_at
+		LIMIT $3
+	`
+
+	rows, err := s.Pg.Pool.Query(ctx, query, statuses, olderThan.Milliseconds(), limit)
+	if err != nil {
+		s.Logger.Error(fmt.Sprintf("Failed to get stale images from database: %v", err))
+		return nil, fmt.Errorf("failed to get stale images from database: %w", err)
+	}
+	defer rows.Close()
+
+	var images []domain.ImgDescriptor
+	for rows.Next() {
+		var img domain.ImgDescriptor
+		err = rows.Scan(&img.ID, &img.Name, &img.URL, &img.ObjectKey, &img.SHA256, &img.Status,
+			&img.CreatedAt, &img.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan stale image: %w", err)
+		}
+		images = append(images, img)
+	}
+
+	return images, rows.Err()
+}
+
+// RequeueImage заново ставит зависшее изображение в очередь worker'а вместе с задачей в outbox.
+// Строка меняется, только если статус и updated_at не изменились с момента StuckImages, иначе
+// возвращается ErrStatusTransition - изображением уже занимается worker
+func (s *Store) RequeueImage(ctx context.Context, img domain.ImgDescriptor, job outbox.Message) error {
+	query := `
+		UPDATE images
+		SET status = 'queued', failure_reason = '', updated_at = now()
+		WHERE image_id = $1 AND status = $2 AND updated_at = $3
+	`
+
+	tx, err := s.Pg.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, query, img.ID, string(img.Status), img.UpdatedAt)
+	if err != nil {
+		s.Logger.Error(fmt.Sprintf("Failed to requeue image in database: %v", err))
+		return fmt.Errorf("failed to requeue image in database: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("image %s (%s) -> %s: %w", img.ID, img.Status, domain.StatusQueued, ErrStatusTransition)
+	}
+
+	err = outbox.Insert(ctx, tx, job)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to commit image requeueing: %w", err)
+	}
+
+	return nil
+}
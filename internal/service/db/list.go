@@ -0,0 +1,245 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/jackc/pgx/v4"
+	"github.com/menyasosali/mts/internal/domain"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ImageOrder - порядок ListImages. Каждый порядок дополняется image_id, чтобы ключ страницы был
+// уникальным
+type ImageOrder int
+
+const (
+	OrderCreatedAtDesc ImageOrder = iota
+	OrderCreatedAtAsc
+	OrderNameAsc
+	OrderNameDesc
+)
+
+// ImageFilter - условия ListImages, пустые поля не ограничивают выборку. CreatedAfter включается
+// в диапазон, CreatedBefore - нет
+type ImageFilter struct {
+	NamePrefix    string
+	Status        domain.ImageStatus
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+}
+
+// ImageCursor - последняя строка предыдущей страницы, следующая начинается строго после нее
+type ImageCursor struct {
+	ID        string
+	Name      string
+	CreatedAt time.Time
+}
+
+// ListImages возвращает до limit изображений страницы, следующей за after (nil - первая страница).
+// Выборка идет по ключу (created_at, image_id) или (name, image_id) и индексам из 011_image_list,
+// без OFFSET. Надгробия удаленных изображений не возвращаются
+func (s *Store) ListImages(ctx context.Context, filter ImageFilter, order ImageOrder, after *ImageCursor, limit int,
+) ([]domain.ImgDescriptor, error) {
+	// имя сравнивается побайтово, как в индексе images_name_idx: так префикс и ключ страницы
+	// не зависят от collation базы
+	sortColumn, direction, cmp := `created_at`, `DESC`, `<`
+	switch order {
+	case OrderCreatedAtAsc:
+		direction, cmp = `ASC`, `>`
+	case OrderNameAsc:
+		sortColumn, direction, cmp = `(name COLLATE "C")`, `ASC`, `>`
+	case OrderNameDesc:
+		sortColumn = `(name COLLATE "C")`
+	}
+
+	conditions := []string{`status <> 'deleted'`}
+	var args []interface{}
+	arg := func(value interface{}) string {
+		args = append(args, value)
+		return "$" + strconv.Itoa(len(args))
+	}
+
+	if filter.NamePrefix != "" {
+		conditions = append(conditions, `(name COLLATE "C") LIKE `+arg(escapeLike(filter.NamePrefix)+"%"))
+	}
+	if filter.Status != "" {
+		conditions = append(conditions, `status = `+arg(string(filter.Status)))
+	}
+	if !filter.CreatedAfter.IsZero() {
+		conditions = append(conditions, `created_at >= `+arg(filter.CreatedAfter))
+	}
+	if !filter.CreatedBefore.IsZero() {
+		conditions = append(conditions, `created_at < `+arg(filter.CreatedBefore))
+	}
+	if after != nil {
+		var key interface{} = after.CreatedAt
+		if order == OrderNameAsc || order == OrderNameDesc {
+			key = after.Name
+		}
+		conditions = append(conditions, fmt.Sprintf(`(%s, image_id) %s (%s, %s)`,
+			sortColumn, cmp, arg(key), arg(after.ID)))
+	}
+
+	query := `
+		SELECT ` + _imageColumns + `
+		FROM images
+		WHERE ` + strings.Join(conditions, " AND ") + `
+		ORDER BY ` + sortColumn + ` ` + direction + `, image_id ` + direction + `
+		LIMIT ` + arg(limit)
+
+	rows, err := s.Pg.Pool.Query(ctx, query, args...)
+	if err != nil {
+		s.Logger.Error(fmt.Sprintf("Failed to list images from database: %v", err))
+		return nil, fmt.Errorf("failed to list images from database: %w", err)
+	}
+
+	images, err := scanImages(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.fillVariants(ctx, images)
+	if err != nil {
+		return nil, err
+	}
+
+	return images, nil
+}
+
+// GetImagesByIDs возвращает найденные изображения из ids одним запросом в порядке ids.
+// Отсутствующих и удаленных изображений в результате нет
+func (s *Store) GetImagesByIDs(ctx context.Context, ids []string) ([]domain.ImgDescriptor, error) {
+	query := `
+		SELECT ` + _imageColumns + `
+		FROM images
+		WHERE image_id = ANY($1::text[]) AND status <> 'deleted'
+		ORDER BY array_position($1::text[], image_id::text)
+	`
+
+	rows, err := s.Pg.Pool.Query(ctx, query, ids)
+	if err != nil {
+		s.Logger.Error(fmt.Sprintf("Failed to get images from database: %v", err))
+		return nil, fmt.Errorf("failed to get images from database: %w", err)
+	}
+
+	images, err := scanImages(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.fillVariants(ctx, images)
+	if err != nil {
+		return nil, err
+	}
+
+	return images, nil
+}
+
+const _imageColumns = `image_id, name, original_url, object_key, COALESCE(sha256, ''), focal_x, focal_y, status,
+			failure_reason, callback_url, metadata, created_at, updated_at`
+
+// scanImages читает строки с колонками _imageColumns и закрывает rows
+func scanImages(rows pgx.Rows) ([]domain.ImgDescriptor, error) {
+	defer rows.Close()
+
+	var images []domain.ImgDescriptor
+	for rows.Next() {
+		var focalX, focalY *float64
+		var metadata []byte
+		var image domain.ImgDescriptor
+		err := rows.Scan(&image.ID, &image.Name, &image.URL, &image.ObjectKey, &image.SHA256, &focalX, &focalY,
+			&image.Status, &image.FailureReason, &image.CallbackURL, &metadata, &image.CreatedAt, &image.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan image: %w", err)
+		}
+		if focalX != nil && focalY != nil {
+			image.FocalPoint = &domain.FocalPoint{X: *focalX, Y: *focalY}
+		}
+		image.Metadata, err = decodeMetadata(metadata)
+		if err != nil {
+			return nil, err
+		}
+		images = append(images, image)
+	}
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("failed to read images from database: %w", rows.Err())
+	}
+
+	return images, nil
+}
+
+// fillVariants загружает превью сразу всех images одним запросом
+func (s *Store) fillVariants(ctx context.Context, images []domain.ImgDescriptor) error {
+	if len(images) == 0 {
+		return nil
+	}
+
+	query := `
+		SELECT image_id, preset, format, content_type, object_key, url, width, height
+		FROM image_variants
+		WHERE image_id = ANY($1)
+		ORDER BY width DESC, preset, format
+	`
+
+	ids := make([]string, 0, len(images))
+	index := make(map[string]int, len(images))
+	for i, image := range images {
+		ids = append(ids, image.ID)
+		index[image.ID] = i
+	}
+
+	rows, err := s.Pg.Pool.Query(ctx, query, ids)
+	if err != nil {
+		s.Logger.Error(fmt.Sprintf("Failed to get image variants from database: %v", err))
+		return fmt.Errorf("failed to get image variants from database: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var imageID string
+		var variant domain.Variant
+		err = rows.Scan(&imageID, &variant.Preset, &variant.Format, &variant.ContentType, &variant.Key, &variant.URL,
+			&variant.Width, &variant.Height)
+		if err != nil {
+			return fmt.Errorf("failed to scan image variant: %w", err)
+		}
+		i := index[imageID]
+		images[i].Variants = append(images[i].Variants, variant)
+	}
+
+	return rows.Err()
+}
+
+// decodeMetadata разбирает images.metadata, NULL - изображение еще не обрабатывалось
+func decodeMetadata(data []byte) (*domain.ImageMetadata, error) {
+	if data == nil {
+		return nil, nil
+	}
+
+	metadata := &domain.ImageMetadata{}
+	err := json.Unmarshal(data, metadata)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image metadata: %w", err)
+	}
+	return metadata, nil
+}
+
+func encodeMetadata(metadata *domain.ImageMetadata) ([]byte, error) {
+	if metadata == nil {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode image metadata: %w", err)
+	}
+	return data, nil
+}
+
+// escapeLike экранирует спецсимволы LIKE, чтобы префикс искался буквально
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
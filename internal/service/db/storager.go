@@ -2,20 +2,47 @@ package db
 
 import (
 	"context"
-	"database/sql"
+	"errors"
 	"fmt"
 	"github.com/google/uuid"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
 	"github.com/menyasosali/mts/internal/domain"
+	"github.com/menyasosali/mts/internal/service/outbox"
 	"github.com/menyasosali/mts/pkg/logger"
 	"github.com/menyasosali/mts/pkg/postgres"
 )
 
 type StoreInterface interface {
-	UploadImage(context.Context, string, string) (string, error)
+	UploadImage(context.Context, domain.ImgDescriptor, outbox.Message) (string, error)
+	QueueImage(context.Context, string, outbox.Message) error
 	GetImageByID(context.Context, string) (*domain.ImgDescriptor, error)
-	UpdateImage(context.Context, domain.ImgDescriptor) error
+	GetImageByHash(context.Context, string) (*domain.ImgDescriptor, error)
+	GetImagesByIDs(context.Context, []string) ([]domain.ImgDescriptor, error)
+	ListImages(context.Context, ImageFilter, ImageOrder, *ImageCursor, int) ([]domain.ImgDescriptor, error)
+	UpdateImage(context.Context, domain.ImgDescriptor, []string) error
+	UpdateImageStatus(context.Context, string, domain.ImageStatus, string) error
+	SetFocalPoint(context.Context, string, domain.FocalPoint) error
+	DeleteImage(context.Context, string) (*domain.ImgDescriptor, error)
+	PurgeImage(context.Context, string) error
+	ReferencedObjects(context.Context, []string) (map[string]bool, error)
+	LockObject(context.Context, string, func(context.Context) error) error
+	DeleteUnreferencedObject(context.Context, string, func(context.Context) error) (bool, error)
+	AddImageCallback(context.Context, string, string) error
+	ListWebhookDeliveries(context.Context, string) ([]domain.WebhookDelivery, error)
+	CreateUpload(context.Context, domain.Upload) (string, error)
+	GetUpload(context.Context, string) (*domain.Upload, error)
+	UpdateUploadProgress(context.Context, string, int64, domain.Upload) error
+	SetUploadOriginal(context.Context, string, string) error
+	DeleteUpload(context.Context, string) error
 }
 
+var (
+	ErrImageNotFound    = errors.New("image not found")
+	ErrImageExists      = errors.New("image already exists")
+	ErrStatusTransition = errors.New("invalid image status transition")
+)
+
 type Store struct {
 	Logger logger.Interface
 	Pg     *postgres.Postgres
@@ -28,63 +55,371 @@ func NewStore(logger logger.Interface, pg *postgres.Postgres) *Store {
 	}
 }
 
-func (s *Store) UploadImage(ctx context.Context, name, originalURL string) (string, error) {
-	image := domain.ImgDescriptor{
-		Name: name,
-		URL:  originalURL,
-	}
+// UploadImage сохраняет новое изображение в статусе queued и в той же транзакции кладет задачу
+// worker'а в outbox. id генерируется, если не задан. Если изображение с таким id или sha256 уже есть,
+// возвращает ErrImageExists
+func (s *Store) UploadImage(ctx context.Context, img domain.ImgDescriptor, job outbox.Message) (string, error) {
 	query := `
-		INSERT INTO images (image_id, name, original_url, url_512, url_256, url_16)
-		VALUES ($1, $2, $3, $4, $5, $6)
-		ON CONFLICT (image_id) DO UPDATE
-		SET name = $2, original_url = $3, url_512 = $4, url_256 = $5, url_16 = $6
+		INSERT INTO images (image_id, name, original_url, object_key, sha256, callback_url, status)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6, 'queued')
+		ON CONFLICT DO NOTHING
 		RETURNING image_id
 	`
 
-	image.ID = uuid.New().String()
-	err := s.Pg.Pool.QueryRow(ctx, query, image.ID, image.Name, image.URL, image.URL512, image.URL256, image.URL16).Scan(&image.ID)
+	if img.ID == "" {
+		img.ID = uuid.New().String()
+	}
+
+	tx, err := s.Pg.Pool.Begin(ctx)
 	if err != nil {
+		return "", fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	err = tx.QueryRow(ctx, query, img.ID, img.Name, img.URL, img.ObjectKey, img.SHA256, img.CallbackURL).
+		Scan(&img.ID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", fmt.Errorf("image %s (sha256 %s): %w", img.ID, img.SHA256, ErrImageExists)
+		}
 		s.Logger.Error(fmt.Sprintf("Failed to save image in database: %v", err))
 		return "", fmt.Errorf("failed to save image in database: %w", err)
 	}
 
-	return image.ID, nil
+	err = outbox.Insert(ctx, tx, job)
+	if err != nil {
+		return "", err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to commit image upload: %w", err)
+	}
+
+	return img.ID, nil
+}
+
+// QueueImage переводит изображение в queued и в той же транзакции кладет задачу worker'а в outbox
+func (s *Store) QueueImage(ctx context.Context, imageID string, job outbox.Message) error {
+	tx, err := s.Pg.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	err = s.updateImageStatus(ctx, tx, imageID, domain.StatusQueued, "")
+	if err != nil {
+		return err
+	}
+
+	err = outbox.Insert(ctx, tx, job)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to commit image queueing: %w", err)
+	}
+
+	return nil
 }
 
 func (s *Store) GetImageByID(ctx context.Context, imageID string) (*domain.ImgDescriptor, error) {
+	return s.getImage(ctx, "image_id", imageID)
+}
+
+// GetImageByHash ищет изображение с тем же содержимым оригинала
+func (s *Store) GetImageByHash(ctx context.Context, sha256 string) (*domain.ImgDescriptor, error) {
+	return s.getImage(ctx, "sha256", sha256)
+}
+
+func (s *Store) getImage(ctx context.Context, column, value string) (*domain.ImgDescriptor, error) {
 	query := `
-		SELECT image_id, name, original_url, url_512, url_256, url_16
+		SELECT image_id, name, original_url, object_key, COALESCE(sha256, ''), focal_x, focal_y, status,
+			failure_reason, callback_url, metadata, created_at, updated_at
 		FROM images
-		WHERE image_id = $1
+		WHERE ` + column + ` = $1 AND status <> 'deleted'
 	`
 
+	var focalX, focalY *float64
+	var metadata []byte
 	image := &domain.ImgDescriptor{}
-	err := s.Pg.Pool.QueryRow(ctx, query, imageID).Scan(&image.ID, &image.Name, &image.URL, &image.URL512, &image.URL256, &image.URL16)
+	err := s.Pg.Pool.QueryRow(ctx, query, value).Scan(&image.ID, &image.Name, &image.URL, &image.ObjectKey,
+		&image.SHA256, &focalX, &focalY, &image.Status, &image.FailureReason, &image.CallbackURL, &metadata,
+		&image.CreatedAt, &image.UpdatedAt)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			s.Logger.Error(fmt.Sprintf("Image not found in database: %v", err))
-			return nil, fmt.Errorf("image not found in database: %v", err)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("image %s=%s: %w", column, value, ErrImageNotFound)
 		}
 		s.Logger.Error(fmt.Sprintf("Failed to get image from database: %v", err))
 		return nil, fmt.Errorf("failed to get image from database: %w", err)
 	}
 
+	if focalX != nil && focalY != nil {
+		image.FocalPoint = &domain.FocalPoint{X: *focalX, Y: *focalY}
+	}
+
+	image.Metadata, err = decodeMetadata(metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	image.Variants, err = s.getVariants(ctx, image.ID)
+	if err != nil {
+		return nil, err
+	}
+
 	return image, nil
 }
 
-func (s *Store) UpdateImage(ctx context.Context, img domain.ImgDescriptor) error {
+func (s *Store) GetFocalPoint(ctx context.Context, imageID string) (*domain.FocalPoint, error) {
+	query := `
+		SELECT focal_x, focal_y
+		FROM images
+		WHERE image_id = $1 AND status <> 'deleted'
+	`
+
+	var focalX, focalY *float64
+	err := s.Pg.Pool.QueryRow(ctx, query, imageID).Scan(&focalX, &focalY)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("image %s: %w", imageID, ErrImageNotFound)
+		}
+		s.Logger.Error(fmt.Sprintf("Failed to get focal point from database: %v", err))
+		return nil, fmt.Errorf("failed to get focal point from database: %w", err)
+	}
+
+	if focalX == nil || focalY == nil {
+		return nil, nil
+	}
+
+	return &domain.FocalPoint{X: *focalX, Y: *focalY}, nil
+}
+
+func (s *Store) SetFocalPoint(ctx context.Context, imageID string, focal domain.FocalPoint) error {
 	query := `
 		UPDATE images
-		SET url_512 = $2, url_256 = $3, url_16 = $4
+		SET focal_x = $2, focal_y = $3, updated_at = now()
+		WHERE image_id = $1 AND status <> 'deleted'
+	`
+
+	tag, err := s.Pg.Pool.Exec(ctx, query, imageID, focal.X, focal.Y)
+	if err != nil {
+		s.Logger.Error(fmt.Sprintf("Failed to save focal point in database: %v", err))
+		return fmt.Errorf("failed to save focal point in database: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("image %s: %w", imageID, ErrImageNotFound)
+	}
+
+	return nil
+}
+
+func (s *Store) getVariants(ctx context.Context, imageID string) ([]domain.Variant, error) {
+	query := `
+		SELECT preset, format, content_type, object_key, url, width, height
+		FROM image_variants
 		WHERE image_id = $1
+		ORDER BY width DESC, preset, format
 	`
 
-	_, err := s.Pg.Pool.Exec(ctx, query, img.ID, img.URL512, img.URL256, img.URL16)
+	rows, err := s.Pg.Pool.Query(ctx, query, imageID)
+	if err != nil {
+		s.Logger.Error(fmt.Sprintf("Failed to get image variants from database: %v", err))
+		return nil, fmt.Errorf("failed to get image variants from database: %w", err)
+	}
+	defer rows.Close()
+
+	var variants []domain.Variant
+	for rows.Next() {
+		var variant domain.Variant
+		err = rows.Scan(&variant.Preset, &variant.Format, &variant.ContentType, &variant.Key, &variant.URL,
+			&variant.Width, &variant.Height)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan image variant: %w", err)
+		}
+		variants = append(variants, variant)
+	}
+
+	return variants, rows.Err()
+}
+
+// UpdateImage сохраняет превью и переводит изображение из processing в ready, из других статусов -
+// ErrStatusTransition. Превью пресетов и форматов, которых больше нет в конфиге, удаляются. Если задан
+// presets, заменяются только превью этих пресетов
+func (s *Store) UpdateImage(ctx context.Context, img domain.ImgDescriptor, presets []string) error {
+	upsertVariant := `
+		INSERT INTO image_variants (image_id, preset, format, content_type, object_key, url, width, height)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (image_id, preset, format) DO UPDATE
+		SET content_type = $4, object_key = $5, url = $6, width = $7, height = $8, created_at = now()
+	`
+	deleteStale := `
+		DELETE FROM image_variants
+		WHERE image_id = $1 AND NOT (preset || '/' || format = ANY($2))
+			AND (cardinality($3::text[]) = 0 OR preset = ANY($3))
+	`
+	// ready только из processing (domain.PreviousStatuses): поздний или повторный результат worker'а
+	// не возвращает в ready изображение, которое уже failed или снова стоит в очереди. metadata без
+	// значения не затирает сохраненные ранее
+	updateImage := `
+		UPDATE images
+		SET status = 'ready', failure_reason = '', metadata = COALESCE($2, metadata), updated_at = now()
+		WHERE image_id = $1 AND status = ANY($3)
+	`
+	selectStatus := `
+		SELECT status
+		FROM images
+		WHERE image_id = $1 AND status <> 'deleted'
+	`
+
+	metadata, err := encodeMetadata(img.Metadata)
+	if err != nil {
+		return err
+	}
+
+	prev := make([]string, 0, len(domain.PreviousStatuses(domain.StatusReady)))
+	for _, st := range domain.PreviousStatuses(domain.StatusReady) {
+		prev = append(prev, string(st))
+	}
+
+	tx, err := s.Pg.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// статус меняется первым: строка блокируется до конца транзакции, превью пишутся только если
+	// переход допустим
+	tag, err := tx.Exec(ctx, updateImage, img.ID, metadata, prev)
 	if err != nil {
 		s.Logger.Error(fmt.Sprintf("Failed to update image in database: %v", err))
 		return fmt.Errorf("failed to update image in database: %w", err)
 	}
+	if tag.RowsAffected() == 0 {
+		var current domain.ImageStatus
+		err = tx.QueryRow(ctx, selectStatus, img.ID).Scan(&current)
+		if errors.Is(err, pgx.ErrNoRows) {
+			// изображение удалили, пока worker его обрабатывал
+			return fmt.Errorf("image %s: %w", img.ID, ErrImageNotFound)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get image status from database: %w", err)
+		}
+		return fmt.Errorf("image %s %s -> %s: %w", img.ID, current, domain.StatusReady, ErrStatusTransition)
+	}
+
+	keep := make([]string, 0, len(img.Variants))
+	for _, variant := range img.Variants {
+		_, err = tx.Exec(ctx, upsertVariant, img.ID, variant.Preset, variant.Format, variant.ContentType, variant.Key,
+			variant.URL, variant.Width, variant.Height)
+		if err != nil {
+			s.Logger.Error(fmt.Sprintf("Failed to save image variant in database: %v", err))
+			return fmt.Errorf("failed to save image variant in database: %w", err)
+		}
+		keep = append(keep, variant.Preset+"/"+variant.Format)
+	}
+
+	if presets == nil {
+		presets = []string{}
+	}
+	_, err = tx.Exec(ctx, deleteStale, img.ID, keep, presets)
+	if err != nil {
+		return fmt.Errorf("failed to delete stale image variants: %w", err)
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to commit image update: %w", err)
+	}
+
+	s.Logger.Info(fmt.Sprintf("The image %s was successfully updated", img.ID))
+	return nil
+}
+
+// DeleteImage ставит изображению надгробие StatusDeleted и возвращает его с превью, чтобы удалить
+// объекты из MinIO. sha256 сбрасывается, чтобы тот же файл можно было загрузить заново. Повторный
+// вызов для надгробия возвращает его же - так удаление можно довести до конца
+func (s *Store) DeleteImage(ctx context.Context, imageID string) (*domain.ImgDescriptor, error) {
+	query := `
+		UPDATE images
+		SET status = 'deleted', sha256 = NULL, updated_at = now()
+		WHERE image_id = $1
+		RETURNING image_id, name, original_url, object_key, status, failure_reason, created_at, updated_at
+	`
+
+	image := &domain.ImgDescriptor{}
+	err := s.Pg.Pool.QueryRow(ctx, query, imageID).Scan(&image.ID, &image.Name, &image.URL, &image.ObjectKey,
+		&image.Status, &image.FailureReason, &image.CreatedAt, &image.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("image %s: %w", imageID, ErrImageNotFound)
+		}
+		s.Logger.Error(fmt.Sprintf("Failed to delete image in database: %v", err))
+		return nil, fmt.Errorf("failed to delete image in database: %w", err)
+	}
+
+	image.Variants, err = s.getVariants(ctx, image.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return image, nil
+}
+
+// PurgeImage удаляет строку надгробия вместе с превью. Живые изображения не трогает
+func (s *Store) PurgeImage(ctx context.Context, imageID string) error {
+	query := `
+		DELETE FROM images
+		WHERE image_id = $1 AND status = 'deleted'
+	`
+
+	tag, err := s.Pg.Pool.Exec(ctx, query, imageID)
+	if err != nil {
+		s.Logger.Error(fmt.Sprintf("Failed to purge image from database: %v", err))
+		return fmt.Errorf("failed to purge image from database: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("image %s: %w", imageID, ErrImageNotFound)
+	}
+
+	return nil
+}
+
+func (s *Store) UpdateImageStatus(ctx context.Context, imageID string, status domain.ImageStatus, reason string) error {
+	return s.updateImageStatus(ctx, s.Pg.Pool, imageID, status, reason)
+}
+
+// execer - *pgxpool.Pool или pgx.Tx
+type execer interface {
+	Exec(context.Context, string, ...interface{}) (pgconn.CommandTag, error)
+}
+
+func (s *Store) updateImageStatus(ctx context.Context, db execer, imageID string, status domain.ImageStatus,
+	reason string) error {
+	query := `
+		UPDATE images
+		SET status = $2, failure_reason = $3, updated_at = now()
+		WHERE image_id = $1 AND status = ANY($4)
+	`
+
+	prev := make([]string, 0, len(domain.PreviousStatuses(status)))
+	for _, st := range domain.PreviousStatuses(status) {
+		prev = append(prev, string(st))
+	}
+
+	tag, err := db.Exec(ctx, query, imageID, string(status), reason, prev)
+	if err != nil {
+		s.Logger.Error(fmt.Sprintf("Failed to update image status in database: %v", err))
+		return fmt.Errorf("failed to update image status in database: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("image %s -> %s: %w", imageID, status, ErrStatusTransition)
+	}
 
-	s.Logger.Info(fmt.Sprintf("The image was successfully updated"))
 	return nil
 }
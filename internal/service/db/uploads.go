@@ -0,0 +1,129 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/menyasosali/mts/internal/domain"
+)
+
+var (
+	ErrUploadNotFound = errors.New("upload not found")
+	ErrUploadConflict = errors.New("upload offset conflict")
+)
+
+func (s *Store) CreateUpload(ctx context.Context, upload domain.Upload) (string, error) {
+	query := `
+		INSERT INTO uploads (upload_id, object_key, multipart_id, filename, content_type, metadata, upload_length)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	upload.ID = uuid.New().String()
+	_, err := s.Pg.Pool.Exec(ctx, query, upload.ID, upload.Key, upload.MultipartID, upload.Filename,
+		upload.ContentType, upload.Metadata, upload.Length)
+	if err != nil {
+		s.Logger.Error(fmt.Sprintf("Failed to save upload in database: %v", err))
+		return "", fmt.Errorf("failed to save upload in database: %w", err)
+	}
+
+	return upload.ID, nil
+}
+
+func (s *Store) GetUpload(ctx context.Context, uploadID string) (*domain.Upload, error) {
+	query := `
+		SELECT upload_id, object_key, multipart_id, filename, content_type, metadata, upload_length, upload_offset,
+			part_count, tail, hash_state, original_key, COALESCE(image_id, ''), created_at, updated_at
+		FROM uploads
+		WHERE upload_id = $1
+	`
+
+	upload := &domain.Upload{}
+	err := s.Pg.Pool.QueryRow(ctx, query, uploadID).Scan(&upload.ID, &upload.Key, &upload.MultipartID,
+		&upload.Filename, &upload.ContentType, &upload.Metadata, &upload.Length, &upload.Offset, &upload.PartCount,
+		&upload.Tail, &upload.HashState, &upload.OriginalKey, &upload.ImageID, &upload.CreatedAt, &upload.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("upload %s: %w", uploadID, ErrUploadNotFound)
+		}
+		s.Logger.Error(fmt.Sprintf("Failed to get upload from database: %v", err))
+		return nil, fmt.Errorf("failed to get upload from database: %w", err)
+	}
+
+	return upload, nil
+}
+
+// UpdateUploadProgress сохраняет принятые байты. Запись меняется, только если offset в базе
+// равен prevOffset, иначе ErrUploadConflict - загрузку параллельно продолжил другой запрос
+func (s *Store) UpdateUploadProgress(ctx context.Context, uploadID string, prevOffset int64, upload domain.Upload,
+) error {
+	query := `
+		UPDATE uploads
+		SET upload_offset = $3, part_count = $4, tail = $5, hash_state = $6, image_id = NULLIF($7, ''),
+			updated_at = now()
+		WHERE upload_id = $1 AND upload_offset = $2
+	`
+
+	tail, hashState := upload.Tail, upload.HashState
+	if tail == nil {
+		tail = []byte{}
+	}
+	if hashState == nil {
+		hashState = []byte{}
+	}
+
+	tag, err := s.Pg.Pool.Exec(ctx, query, uploadID, prevOffset, upload.Offset, upload.PartCount, tail, hashState,
+		upload.ImageID)
+	if err != nil {
+		s.Logger.Error(fmt.Sprintf("Failed to update upload in database: %v", err))
+		return fmt.Errorf("failed to update upload in database: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("upload %s at offset %d: %w", uploadID, prevOffset, ErrUploadConflict)
+	}
+
+	return nil
+}
+
+// SetUploadOriginal запоминает ключ оригинала собранной загрузки, после этого multipart-загрузки
+// в MinIO уже нет
+func (s *Store) SetUploadOriginal(ctx context.Context, uploadID, originalKey string) error {
+	query := `
+		UPDATE uploads
+		SET original_key = $2, updated_at = now()
+		WHERE upload_id = $1
+	`
+
+	tag, err := s.Pg.Pool.Exec(ctx, query, uploadID, originalKey)
+	if err != nil {
+		s.Logger.Error(fmt.Sprintf("Failed to update upload in database: %v", err))
+		return fmt.Errorf("failed to update upload in database: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("upload %s: %w", uploadID, ErrUploadNotFound)
+	}
+
+	return nil
+}
+
+func (s *Store) DeleteUpload(ctx context.Context, uploadID string) error {
+	query := `
+		DELETE FROM uploads
+		WHERE upload_id = $1
+	`
+
+	tag, err := s.Pg.Pool.Exec(ctx, query, uploadID)
+	if err != nil {
+		s.Logger.Error(fmt.Sprintf("Failed to delete upload from database: %v", err))
+		return fmt.Errorf("failed to delete upload from database: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("upload %s: %w", uploadID, ErrUploadNotFound)
+	}
+
+	return nil
+}
@@ -0,0 +1,225 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v4"
+	"github.com/menyasosali/mts/internal/domain"
+	"time"
+)
+
+// AddImageCallback подписывает url на завершение обработки существующего изображения. Если оно уже
+// ready или failed, доставка ставится сразу, иначе ее поставит триггер images_webhook. Строка
+// изображения блокируется, чтобы смена статуса не прошла между проверкой и подпиской
+func (s *Store) AddImageCallback(ctx context.Context, imageID, url string) error {
+	selectStatus := `
+		SELECT status, callback_url
+		FROM images
+		WHERE image_id = $1 AND status <> 'deleted'
+		FOR UPDATE
+	`
+	insertDelivery := `
+		INSERT INTO webhook_deliveries (image_id, url, event)
+		VALUES ($1, $2, 'image.' || $3)
+	`
+	insertCallback := `
+		INSERT INTO image_callbacks (image_id, url)
+		VALUES ($1, $2)
+		ON CONFLICT DO NOTHING
+	`
+
+	tx, err := s.Pg.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var status, callbackURL string
+	err = tx.QueryRow(ctx, selectStatus, imageID).Scan(&status, &callbackURL)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrImageNotFound
+		}
+		return fmt.Errorf("failed to get image status: %w", err)
+	}
+
+	switch domain.ImageStatus(status) {
+	case domain.StatusReady, domain.StatusFailed:
+		_, err = tx.Exec(ctx, insertDelivery, imageID, url, status)
+	default:
+		if url == callbackURL {
+			// этот адрес триггер и так уведомит
+			return nil
+		}
+		_, err = tx.Exec(ctx, insertCallback, imageID, url)
+	}
+	if err != nil {
+		s.Logger.Error(fmt.Sprintf("Failed to add image callback: %v", err))
+		return fmt.Errorf("failed to add image callback: %w", err)
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to commit image callback: %w", err)
+	}
+
+	return nil
+}
+
+// ClaimWebhookDeliveries забирает до limit доставок, которым пора делать попытку, и откладывает их
+// на lease: пока попытка идет, другие экземпляры gateway их не возьмут, а если процесс упадет,
+// доставка вернется в работу после lease
+func (s *Store) ClaimWebhookDeliveries(ctx context.Context, limit int, lease time.Duration,
+) ([]domain.WebhookDelivery, error) {
+	query := `
+		UPDATE webhook_deliveries
+		SET next_attempt_at = now() + $2 * interval '1 millisecond'
+		WHERE delivery_id IN (
+			SELECT delivery_id
+			FROM webhook_deliveries
+			WHERE status = 'pending' AND next_attempt_at <= now()
+			ORDER BY next_attempt_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING delivery_id, image_id, url, event, payload, status, attempts, last_status_code, last_error,
+			next_attempt_at, created_at, delivered_at
+	`
+
+	rows, err := s.Pg.Pool.Query(ctx, query, limit, lease.Milliseconds())
+	if err != nil {
+		s.Logger.Error(fmt.Sprintf("Failed to claim webhook deliveries: %v", err))
+		return nil, fmt.Errorf("failed to claim webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	var deliveries []domain.WebhookDelivery
+	for rows.Next() {
+		var delivery domain.WebhookDelivery
+		err = rows.Scan(&delivery.ID, &delivery.ImageID, &delivery.URL, &delivery.Event, &delivery.Payload,
+			&delivery.Status, &delivery.Attempts, &delivery.LastStatusCode, &delivery.LastError,
+			&delivery.NextAttemptAt, &delivery.CreatedAt, &delivery.DeliveredAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan webhook delivery: %w", err)
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	return deliveries, rows.Err()
+}
+
+// RecordWebhookAttempt сохраняет попытку в журнал и новое состояние доставки
+func (s *Store) RecordWebhookAttempt(ctx context.Context, delivery domain.WebhookDelivery,
+	attempt domain.WebhookAttempt) error {
+	updateDelivery := `
+		UPDATE webhook_deliveries
+		SET payload = $2, status = $3, attempts = $4, last_status_code = $5, last_error = $6,
+			next_attempt_at = $7, delivered_at = $8
+		WHERE delivery_id = $1
+	`
+	insertAttempt := `
+		INSERT INTO webhook_attempts (delivery_id, attempt, status_code, error, duration_ms)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+
+	tx, err := s.Pg.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, updateDelivery, delivery.ID, delivery.Payload, string(delivery.Status), delivery.Attempts,
+		delivery.LastStatusCode, delivery.LastError, delivery.NextAttemptAt, delivery.DeliveredAt)
+	if err != nil {
+		s.Logger.Error(fmt.Sprintf("Failed to update webhook delivery: %v", err))
+		return fmt.Errorf("failed to update webhook delivery: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, insertAttempt, delivery.ID, attempt.Attempt, attempt.StatusCode, attempt.Error,
+		attempt.Duration.Milliseconds())
+	if err != nil {
+		s.Logger.Error(fmt.Sprintf("Failed to save webhook attempt: %v", err))
+		return fmt.Errorf("failed to save webhook attempt: %w", err)
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to commit webhook attempt: %w", err)
+	}
+
+	return nil
+}
+
+// ListWebhookDeliveries возвращает доставки изображения с журналом попыток, новые первыми
+func (s *Store) ListWebhookDeliveries(ctx context.Context, imageID string) ([]domain.WebhookDelivery, error) {
+	selectDeliveries := `
+		SELECT delivery_id, image_id, url, event, status, attempts, last_status_code, last_error,
+			next_attempt_at, created_at, delivered_at
+		FROM webhook_deliveries
+		WHERE image_id = $1
+		ORDER BY delivery_id DESC
+	`
+	selectAttempts := `
+		SELECT a.delivery_id, a.attempt, a.status_code, a.error, a.duration_ms, a.created_at
+		FROM webhook_attempts a
+		JOIN webhook_deliveries d ON d.delivery_id = a.delivery_id
+		WHERE d.image_id = $1
+		ORDER BY a.delivery_id, a.attempt
+	`
+
+	rows, err := s.Pg.Pool.Query(ctx, selectDeliveries, imageID)
+	if err != nil {
+		s.Logger.Error(fmt.Sprintf("Failed to get webhook deliveries from database: %v", err))
+		return nil, fmt.Errorf("failed to get webhook deliveries from database: %w", err)
+	}
+	defer rows.Close()
+
+	var deliveries []domain.WebhookDelivery
+	index := make(map[int64]int)
+	for rows.Next() {
+		var delivery domain.WebhookDelivery
+		err = rows.Scan(&delivery.ID, &delivery.ImageID, &delivery.URL, &delivery.Event, &delivery.Status,
+			&delivery.Attempts, &delivery.LastStatusCode, &delivery.LastError, &delivery.NextAttemptAt,
+			&delivery.CreatedAt, &delivery.DeliveredAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan webhook delivery: %w", err)
+		}
+		index[delivery.ID] = len(deliveries)
+		deliveries = append(deliveries, delivery)
+	}
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("failed to get webhook deliveries from database: %w", rows.Err())
+	}
+	rows.Close()
+
+	if len(deliveries) == 0 {
+		return nil, nil
+	}
+
+	rows, err = s.Pg.Pool.Query(ctx, selectAttempts, imageID)
+	if err != nil {
+		s.Logger.Error(fmt.Sprintf("Failed to get webhook attempts from database: %v", err))
+		return nil, fmt.Errorf("failed to get webhook attempts from database: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var deliveryID int64
+		var durationMs int64
+		var attempt domain.WebhookAttempt
+		err = rows.Scan(&deliveryID, &attempt.Attempt, &attempt.StatusCode, &attempt.Error, &durationMs,
+			&attempt.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan webhook attempt: %w", err)
+		}
+		attempt.Duration = time.Duration(durationMs) * time.Millisecond
+
+		i, ok := index[deliveryID]
+		if ok {
+			deliveries[i].AttemptLog = append(deliveries[i].AttemptLog, attempt)
+		}
+	}
+
+	return deliveries, rows.Err()
+}
@@ -0,0 +1,133 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"github.com/menyasosali/mts/internal/domain"
+	"github.com/menyasosali/mts/pkg/logger"
+	"sync"
+	"time"
+)
+
+const (
+	_subscriberBuffer = 16
+	_reconnectDelay   = time.Second
+)
+
+// Source - поток событий изображений из базы (db.Store.ListenImageEvents)
+type Source interface {
+	ListenImageEvents(ctx context.Context, listening func(), handle func(domain.ImageEvent)) error
+}
+
+// Hub раздает события изображений подписчикам этого экземпляра gateway. У каждого экземпляра
+// свое соединение LISTEN, поэтому событие worker'а видят наблюдатели на всех репликах.
+// Канал подписчика закрывается, если он отстал, соединение оборвалось или LISTEN восстановлен
+// после обрыва: события могли потеряться, подписчик должен перечитать состояние и подписаться заново
+type Hub struct {
+	Logger logger.Interface
+	Source Source
+
+	mu          sync.Mutex
+	subscribers map[string]map[chan domain.ImageEvent]struct{}
+}
+
+func NewHub(logger logger.Interface, source Source) *Hub {
+	return &Hub{
+		Logger:      logger,
+		Source:      source,
+		subscribers: make(map[string]map[chan domain.ImageEvent]struct{}),
+	}
+}
+
+func (h *Hub) Start(ctx context.Context) {
+	h.Logger.Info("Image events hub started")
+	go h.run(ctx)
+}
+
+func (h *Hub) run(ctx context.Context) {
+	for {
+		err := h.Source.ListenImageEvents(ctx, h.listening, h.publish)
+		if ctx.Err() != nil {
+			h.Logger.Info("Image events hub stopped")
+			return
+		}
+
+		h.Logger.Error(fmt.Sprintf("Image events listener failed, reconnect in %s: %v", _reconnectDelay, err))
+		h.closeAll()
+
+		select {
+		case <-time.After(_reconnectDelay):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Subscribe подписывается на события изображения imageID. cancel нужно вызвать, когда события
+// больше не нужны; после закрытия канала хабом вызывать его тоже можно
+func (h *Hub) Subscribe(imageID string) (<-chan domain.ImageEvent, func()) {
+	ch := make(chan domain.ImageEvent, _subscriberBuffer)
+
+	h.mu.Lock()
+	if h.subscribers[imageID] == nil {
+		h.subscribers[imageID] = make(map[chan domain.ImageEvent]struct{})
+	}
+	h.subscribers[imageID][ch] = struct{}{}
+	h.mu.Unlock()
+
+	cancel := func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		h.remove(imageID, ch)
+	}
+
+	return ch, cancel
+}
+
+// listening вызывается, когда LISTEN снова работает. Подписчики, пришедшие без LISTEN, могли
+// пропустить уведомления между чтением состояния и переподключением - закрываем всех
+func (h *Hub) listening() {
+	h.closeAll()
+}
+
+func (h *Hub) publish(event domain.ImageEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for ch := range h.subscribers[event.ImageID] {
+		select {
+		case ch <- event:
+		default:
+			// подписчик не успевает читать, он перечитает состояние сам
+			h.remove(event.ImageID, ch)
+		}
+	}
+}
+
+func (h *Hub) closeAll() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for imageID, subscribers := range h.subscribers {
+		for ch := range subscribers {
+			h.remove(imageID, ch)
+		}
+	}
+}
+
+// remove закрывает канал подписчика, если он еще подписан. Вызывается под h.mu
+func (h *Hub) remove(imageID string, ch chan domain.ImageEvent) {
+	subscribers, ok := h.subscribers[imageID]
+	if !ok {
+		return
+	}
+	if _, ok = subscribers[ch]; !ok {
+		return
+	}
+
+	delete(subscribers, ch)
+	close(ch)
+	if len(subscribers) == 0 {
+		delete(h.subscribers, imageID)
+	}
+}
@@ -5,6 +5,9 @@ import (
 	"fmt"
 	"github.com/menyasosali/mts/internal/service/minio"
 	"github.com/menyasosali/mts/pkg/logger"
+	miniogo "github.com/minio/minio-go/v7"
+	"io"
+	"time"
 )
 
 //структура принимает клиент бд, минио, контекст, logger и предоставляет 2 функции uploaadImage getimagebyID
@@ -14,8 +17,32 @@ import (
 //
 
 type FileStorerInterface interface {
-	UploadImage(context.Context, []byte, string) (string, error)
+	UploadImage(context.Context, io.Reader, int64, string, string) (string, error)
 	DownloadImage(context.Context, string) ([]byte, error)
+	OpenImage(context.Context, string) (*ImageObject, error)
+	StatImage(context.Context, string) (ImageInfo, error)
+	ImageURL(context.Context, string) (string, error)
+	DeleteImage(context.Context, string) error
+	ListImages(context.Context, string) ([]string, error)
+	CopyImage(context.Context, string, string) error
+	PresignUpload(context.Context, string, string, int64, time.Duration) (string, error)
+	StartUpload(context.Context, string, string) (string, error)
+	UploadPart(context.Context, string, string, int, io.Reader, int64) error
+	CompleteUpload(context.Context, string, string, int) (string, error)
+	AbortUpload(context.Context, string, string) error
+}
+
+type ImageInfo struct {
+	Size         int64
+	ETag         string
+	ContentType  string
+	LastModified time.Time
+}
+
+// ImageObject - открытый на чтение объект хранилища, закрывается вызывающим
+type ImageObject struct {
+	io.ReadSeekCloser
+	ImageInfo
 }
 
 type FileStorer struct {
@@ -30,8 +57,10 @@ func NewFileStorer(logger logger.Interface, minioClient *minio.ClientMinio) *Fil
 	}
 }
 
-func (u *FileStorer) UploadImage(ctx context.Context, imageBytes []byte, filename string) (string, error) {
-	fileURL, err := u.ClientMinio.UploadFile(ctx, imageBytes, filename)
+// UploadImage сохраняет файл потоком, size = -1 если размер заранее неизвестен
+func (u *FileStorer) UploadImage(ctx context.Context, image io.Reader, size int64, filename, contentType string,
+) (string, error) {
+	fileURL, err := u.ClientMinio.UploadFile(ctx, image, size, filename, contentType)
 
 	if err != nil {
 		u.Logger.Error(fmt.Sprintf("Failed to upload image to MinIO: %v", err))
@@ -51,3 +80,79 @@ func (u *FileStorer) DownloadImage(ctx context.Context, imageID string) ([]byte,
 
 	return originalImageBytes, nil
 }
+
+func (u *FileStorer) OpenImage(ctx context.Context, key string) (*ImageObject, error) {
+	object, info, err := u.ClientMinio.OpenFile(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open image: %w", err)
+	}
+
+	return &ImageObject{
+		ReadSeekCloser: object,
+		ImageInfo:      imageInfo(info),
+	}, nil
+}
+
+func (u *FileStorer) StatImage(ctx context.Context, key string) (ImageInfo, error) {
+	info, err := u.ClientMinio.StatFile(ctx, key)
+	if err != nil {
+		return ImageInfo{}, fmt.Errorf("failed to stat image: %w", err)
+	}
+
+	return imageInfo(info), nil
+}
+
+func (u *FileStorer) ImageURL(ctx context.Context, key string) (string, error) {
+	return u.ClientMinio.GetObjectURL(ctx, key)
+}
+
+func (u *FileStorer) DeleteImage(ctx context.Context, key string) error {
+	err := u.ClientMinio.DeleteFile(ctx, key)
+	if err != nil {
+		return fmt.Errorf("failed to delete image: %w", err)
+	}
+
+	return nil
+}
+
+// ListImages возвращает ключи объектов с префиксом prefix
+func (u *FileStorer) ListImages(ctx context.Context, prefix string) ([]string, error) {
+	var keys []string
+	for object := range u.ClientMinio.ListFiles(ctx, prefix) {
+		if object.Err != nil {
+			return nil, fmt.Errorf("failed to list images: %w", object.Err)
+		}
+		keys = append(keys, object.Key)
+	}
+
+	return keys, nil
+}
+
+func (u *FileStorer) CopyImage(ctx context.Context, src, dst string) error {
+	err := u.ClientMinio.CopyFile(ctx, src, dst)
+	if err != nil {
+		return fmt.Errorf("failed to copy image: %w", err)
+	}
+
+	return nil
+}
+
+// PresignUpload выдает url, по которому клиент загружает оригинал размером size в MinIO в обход gateway
+func (u *FileStorer) PresignUpload(ctx context.Context, key, contentType string, size int64, expires time.Duration,
+) (string, error) {
+	uploadURL, err := u.ClientMinio.PresignUpload(ctx, key, contentType, size, expires)
+	if err != nil {
+		return "", fmt.Errorf("failed to presign image upload: %w", err)
+	}
+
+	return uploadURL, nil
+}
+
+func imageInfo(info miniogo.ObjectInfo) ImageInfo {
+	return ImageInfo{
+		Size:         info.Size,
+		ETag:         info.ETag,
+		ContentType:  info.ContentType,
+		LastModified: info.LastModified,
+	}
+}
@@ -0,0 +1,47 @@
+package filestorer
+
+import (
+	"context"
+	"fmt"
+	"io"
+)
+
+// методы для загрузки оригинала частями (tus), поверх multipart-загрузки MinIO
+
+func (u *FileStorer) StartUpload(ctx context.Context, filename, contentType string) (string, error) {
+	uploadID, err := u.ClientMinio.NewMultipartUpload(ctx, filename, contentType)
+	if err != nil {
+		return "", fmt.Errorf("failed to start image upload: %w", err)
+	}
+
+	return uploadID, nil
+}
+
+func (u *FileStorer) UploadPart(ctx context.Context, filename, uploadID string, partNumber int, data io.Reader,
+	size int64) error {
+	err := u.ClientMinio.UploadPart(ctx, filename, uploadID, partNumber, data, size)
+	if err != nil {
+		return fmt.Errorf("failed to upload image part: %w", err)
+	}
+
+	return nil
+}
+
+func (u *FileStorer) CompleteUpload(ctx context.Context, filename, uploadID string, partCount int) (string, error) {
+	fileURL, err := u.ClientMinio.CompleteMultipartUpload(ctx, filename, uploadID, partCount)
+	if err != nil {
+		u.Logger.Error(fmt.Sprintf("Failed to complete image upload: %v", err))
+		return "", fmt.Errorf("failed to complete image upload: %w", err)
+	}
+
+	return fileURL, nil
+}
+
+func (u *FileStorer) AbortUpload(ctx context.Context, filename, uploadID string) error {
+	err := u.ClientMinio.AbortMultipartUpload(ctx, filename, uploadID)
+	if err != nil {
+		return fmt.Errorf("failed to abort image upload: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,306 @@
+package gc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/menyasosali/mts/config"
+	"github.com/menyasosali/mts/internal/domain"
+	"github.com/menyasosali/mts/internal/service/db"
+	"github.com/menyasosali/mts/internal/service/kafka"
+	"github.com/menyasosali/mts/internal/service/minio"
+	"github.com/menyasosali/mts/internal/service/outbox"
+	"github.com/menyasosali/mts/pkg/logger"
+	miniogo "github.com/minio/minio-go/v7"
+	"time"
+)
+
+// Сборка мусора: надгробия удаленных изображений, которые gateway не смог убрать до конца, объекты
+// MinIO, на которые не ссылается база (брошенные загрузки uploads/..., оригиналы и превью удаленных
+// строк), и изображения, задача которых потерялась по пути к worker'у
+
+// ObjectStore - бакет MinIO (minio.ClientMinio)
+type ObjectStore interface {
+	ListFiles(ctx context.Context, prefix string) <-chan miniogo.ObjectInfo
+	StatFile(ctx context.Context, filename string) (miniogo.ObjectInfo, error)
+	DeleteFile(ctx context.Context, filename string) error
+}
+
+// ImageIndex - то, что сборщику нужно от базы (db.Store)
+type ImageIndex interface {
+	ReferencedObjects(ctx context.Context, keys []string) (map[string]bool, error)
+	DeleteUnreferencedObject(ctx context.Context, key string, remove func(context.Context) error) (bool, error)
+	StuckImages(ctx context.Context, olderThan time.Duration, limit int) ([]domain.ImgDescriptor, error)
+	DeletedImages(ctx context.Context, olderThan time.Duration, limit int) ([]domain.ImgDescriptor, error)
+	PurgeImage(ctx context.Context, imageID string) error
+	RequeueImage(ctx context.Context, img domain.ImgDescriptor, job outbox.Message) error
+	UpdateImageStatus(ctx context.Context, imageID string, status domain.ImageStatus, reason string) error
+}
+
+// Report - итог одного прохода. В dry run Purged, Deleted, Requeued и Failed считают то, что было
+// бы сделано
+type Report struct {
+	DryRun       bool
+	Purged       int
+	Scanned      int
+	Orphaned     int
+	OrphanedSize int64
+	Deleted      int
+	Stuck        int
+	Requeued     int
+	Failed       int
+	Errors       int
+}
+
+func (r Report) String() string {
+	return fmt.Sprintf("GC report (dry run: %t): purged %d deleted images; scanned %d objects, "+
+		"orphaned %d (%d bytes), deleted %d; stuck images %d, requeued %d, marked failed %d; errors %d",
+		r.DryRun, r.Purged, r.Scanned, r.Orphaned, r.OrphanedSize, r.Deleted, r.Stuck, r.Requeued, r.Failed, r.Errors)
+}
+
+type Collector struct {
+	Logger   logger.Interface
+	Objects  ObjectStore
+	Images   ImageIndex
+	JobTopic string
+	Cfg      config.GCConfig
+}
+
+func NewCollector(logger logger.Interface, objects ObjectStore, images ImageIndex, jobTopic string,
+	cfg config.GCConfig) *Collector {
+	return &Collector{
+		Logger:   logger,
+		Objects:  objects,
+		Images:   images,
+		JobTopic: jobTopic,
+		Cfg:      cfg,
+	}
+}
+
+// Start запускает Collect каждые Cfg.Interval до отмены ctx. При нулевом Interval ничего не делает
+func (c *Collector) Start(ctx context.Context) {
+	if c.Cfg.Interval <= 0 {
+		c.Logger.Info("GC is disabled")
+		return
+	}
+
+	c.Logger.Info(fmt.Sprintf("GC started, interval %s", c.Cfg.Interval))
+	go func() {
+		ticker := time.NewTicker(c.Cfg.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				c.Logger.Info("GC stopped")
+				return
+			case <-ticker.C:
+				report, err := c.Collect(ctx)
+				if err != nil {
+					c.Logger.Error(fmt.Sprintf("GC error: %v", err))
+				}
+				c.Logger.Info(report.String())
+			}
+		}
+	}()
+}
+
+// Collect делает один проход: убирает надгробия, удаляет объекты без ссылок и разбирает зависшие
+// изображения. Ошибки отдельных объектов и строк считаются в Report.Errors, проход при этом
+// продолжается
+func (c *Collector) Collect(ctx context.Context) (Report, error) {
+	report := Report{DryRun: c.Cfg.DryRun}
+
+	// сначала строки надгробий: после них объекты удаленных изображений остаются без ссылок
+	err := c.collectDeletedImages(ctx, &report)
+	if err != nil {
+		return report, err
+	}
+
+	err = c.collectObjects(ctx, &report)
+	if err != nil {
+		return report, err
+	}
+
+	err = c.collectStuckImages(ctx, &report)
+	if err != nil {
+		return report, err
+	}
+
+	return report, nil
+}
+
+func (c *Collector) collectDeletedImages(ctx context.Context, report *Report) error {
+	images, err := c.Images.DeletedImages(ctx, c.Cfg.StuckAfter, c.Cfg.BatchSize)
+	if err != nil {
+		return err
+	}
+
+	for _, img := range images {
+		c.Logger.Info(fmt.Sprintf("GC: purge deleted image %s (deleted %s)",
+			img.ID, img.UpdatedAt.Format(time.RFC3339)))
+		if !c.Cfg.DryRun {
+			err = c.Images.PurgeImage(ctx, img.ID)
+			if err != nil && !errors.Is(err, db.ErrImageNotFound) {
+				c.Logger.Error(fmt.Sprintf("GC: failed to purge image %s: %v", img.ID, err))
+				report.Errors++
+				continue
+			}
+		}
+		report.Purged++
+	}
+
+	return nil
+}
+
+func (c *Collector) collectObjects(ctx context.Context, report *Report) error {
+	// отмена останавливает листинг, если проход прервался на ошибке базы
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// объекты моложе GracePeriod могут принадлежать загрузке, которая еще не записана в базу
+	cutoff := time.Now().Add(-c.Cfg.GracePeriod)
+	batch := make([]miniogo.ObjectInfo, 0, c.Cfg.BatchSize)
+
+	for object := range c.Objects.ListFiles(ctx, "") {
+		if object.Err != nil {
+			return fmt.Errorf("failed to list objects: %w", object.Err)
+		}
+
+		report.Scanned++
+		if object.LastModified.After(cutoff) {
+			continue
+		}
+
+		batch = append(batch, object)
+		if len(batch) == c.Cfg.BatchSize {
+			err := c.sweepObjects(ctx, batch, report)
+			if err != nil {
+				return err
+			}
+			batch = batch[:0]
+		}
+	}
+
+	if len(batch) > 0 {
+		return c.sweepObjects(ctx, batch, report)
+	}
+	return ctx.Err()
+}
+
+func (c *Collector) sweepObjects(ctx context.Context, objects []miniogo.ObjectInfo, report *Report) error {
+	keys := make([]string, 0, len(objects))
+	for _, object := range objects {
+		keys = append(keys, object.Key)
+	}
+
+	referenced, err := c.Images.ReferencedObjects(ctx, keys)
+	if err != nil {
+		return err
+	}
+
+	for _, object := range objects {
+		if referenced[object.Key] {
+			continue
+		}
+
+		report.Orphaned++
+		report.OrphanedSize += object.Size
+		c.Logger.Info(fmt.Sprintf("GC: orphaned object %s (%d bytes, modified %s)",
+			object.Key, object.Size, object.LastModified.Format(time.RFC3339)))
+
+		if !c.Cfg.DryRun {
+			// ссылки проверяются еще раз под блокировкой ключа: оригинал с тем же sha256 мог быть
+			// загружен заново после проверки батча
+			key := object.Key
+			deleted, err := c.Images.DeleteUnreferencedObject(ctx, key, func(ctx context.Context) error {
+				return c.Objects.DeleteFile(ctx, key)
+			})
+			if err != nil {
+				c.Logger.Error(fmt.Sprintf("GC: failed to delete object %s: %v", key, err))
+				report.Errors++
+				continue
+			}
+			if !deleted {
+				continue
+			}
+		}
+		report.Deleted++
+	}
+
+	return nil
+}
+
+func (c *Collector) collectStuckImages(ctx context.Context, report *Report) error {
+	images, err := c.Images.StuckImages(ctx, c.Cfg.StuckAfter, c.Cfg.BatchSize)
+	if err != nil {
+		return err
+	}
+
+	for _, img := range images {
+		report.Stuck++
+
+		_, err = c.Objects.StatFile(ctx, img.ObjectKey)
+		switch {
+		case errors.Is(err, minio.ErrFileNotFound):
+			c.failImage(ctx, img, "original object is missing", report)
+		case err != nil:
+			c.Logger.Error(fmt.Sprintf("GC: failed to stat original of image %s: %v", img.ID, err))
+			report.Errors++
+		case time.Since(img.CreatedAt) < c.Cfg.RequeueWindow:
+			c.requeueImage(ctx, img, report)
+		default:
+			c.failImage(ctx, img, fmt.Sprintf("stuck in %s since %s", img.Status,
+				img.UpdatedAt.Format(time.RFC3339)), report)
+		}
+	}
+
+	return nil
+}
+
+func (c *Collector) requeueImage(ctx context.Context, img domain.ImgDescriptor, report *Report) {
+	c.Logger.Info(fmt.Sprintf("GC: requeue image %s stuck in %s since %s",
+		img.ID, img.Status, img.UpdatedAt.Format(time.RFC3339)))
+	if c.Cfg.DryRun {
+		report.Requeued++
+		return
+	}
+
+	payload, err := kafka.NewImageJob(img).Marshal()
+	if err != nil {
+		c.Logger.Error(fmt.Sprintf("GC: failed to marshal job of image %s: %v", img.ID, err))
+		report.Errors++
+		return
+	}
+
+	err = c.Images.RequeueImage(ctx, img, outbox.Message{Topic: c.JobTopic, Key: img.ID, Payload: payload})
+	switch {
+	case errors.Is(err, db.ErrStatusTransition):
+		// пока шел проход, изображение сдвинулось с места
+		c.Logger.Info(fmt.Sprintf("GC: image %s changed status, skip requeue", img.ID))
+	case err != nil:
+		c.Logger.Error(fmt.Sprintf("GC: failed to requeue image %s: %v", img.ID, err))
+		report.Errors++
+	default:
+		report.Requeued++
+	}
+}
+
+func (c *Collector) failImage(ctx context.Context, img domain.ImgDescriptor, reason string, report *Report) {
+	c.Logger.Info(fmt.Sprintf("GC: mark image %s as failed: %s", img.ID, reason))
+	if c.Cfg.DryRun {
+		report.Failed++
+		return
+	}
+
+	err := c.Images.UpdateImageStatus(ctx, img.ID, domain.StatusFailed, "gc: "+reason)
+	switch {
+	case errors.Is(err, db.ErrStatusTransition):
+		c.Logger.Info(fmt.Sprintf("GC: image %s changed status, skip marking failed", img.ID))
+	case err != nil:
+		c.Logger.Error(fmt.Sprintf("GC: failed to mark image %s as failed: %v", img.ID, err))
+		report.Errors++
+	default:
+		report.Failed++
+	}
+}
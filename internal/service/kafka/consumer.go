@@ -3,39 +3,68 @@ package kafka
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/Shopify/sarama"
 	"github.com/menyasosali/mts/config"
 	"github.com/menyasosali/mts/internal/domain"
+	"github.com/menyasosali/mts/internal/service/db"
 	"github.com/menyasosali/mts/pkg/logger"
+	"time"
 )
 
 type ImageProcessor interface {
-	ProcessImage(context.Context, ImgKafka) domain.ImgDescriptor
+	ProcessImage(context.Context, ImageJob) (domain.ImgDescriptor, error)
+}
+
+// ImageSink сохраняет результат и статус обработки изображения (db.Store)
+type ImageSink interface {
+	UpdateImage(context.Context, domain.ImgDescriptor, []string) error
+	UpdateImageStatus(context.Context, string, domain.ImageStatus, string) error
 }
 
 type ImageConsumer struct {
-	Logger    logger.Interface
-	Processor ImageProcessor
-	Consumer  sarama.Consumer
-	Cfg       config.KafkaConfig
+	Logger      logger.Interface
+	Processor   ImageProcessor
+	Sink        ImageSink
+	DeadLetters MessageProducer
+	Quarantine  MessageProducer
+	Group       sarama.ConsumerGroup
+	Cfg         config.KafkaConfig
 }
 
-func NewImageConsumer(logger logger.Interface, processor ImageProcessor, cfg config.KafkaConfig,
-) (*ImageConsumer, error) {
+var _ sarama.ConsumerGroupHandler = (*ImageConsumer)(nil)
+
+// пауза перед новой сессией после ошибки Group.Consume (брокеры недоступны, ошибка ребалансировки),
+// удваивается до _maxSessionRetryDelay
+const (
+	_sessionRetryDelay    = time.Second
+	_maxSessionRetryDelay = 30 * time.Second
+)
+
+// NewImageConsumer - deadLetters принимает задачи, упавшие после всех попыток, quarantine - задачи
+// неизвестной версии схемы, которые этот worker не умеет разбирать
+func NewImageConsumer(logger logger.Interface, processor ImageProcessor, sink ImageSink, deadLetters,
+	quarantine MessageProducer, cfg config.KafkaConfig) (*ImageConsumer, error) {
 	config := sarama.NewConfig()
 	config.Consumer.Return.Errors = true
+	config.Consumer.Offsets.Initial = sarama.OffsetOldest
+	config.Consumer.Offsets.AutoCommit.Enable = true
+	config.Consumer.Group.Rebalance.GroupStrategies = []sarama.BalanceStrategy{sarama.BalanceStrategySticky}
 
-	conn, err := sarama.NewConsumer(cfg.Brokers, config)
+	group, err := sarama.NewConsumerGroup(cfg.Brokers, cfg.GroupID, config)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create Kafka conn: %w", err)
+		return nil, fmt.Errorf("failed to create Kafka consumer group: %w", err)
 	}
 
 	imageConsumer := &ImageConsumer{
-		Logger:    logger,
-		Processor: processor,
-		Consumer:  conn,
-		Cfg:       cfg,
+		Logger:      logger,
+		Processor:   processor,
+		Sink:        sink,
+		DeadLetters: deadLetters,
+		Quarantine:  quarantine,
+		Group:       group,
+		Cfg:         cfg,
 	}
 
 	return imageConsumer, nil
@@ -47,57 +76,202 @@ func (c *ImageConsumer) Start(ctx context.Context) {
 }
 
 func (c *ImageConsumer) Close() error {
-	if c.Consumer != nil {
-		err := c.Consumer.Close()
+	if c.Group != nil {
+		err := c.Group.Close()
 		if err != nil {
-			return fmt.Errorf("failed to close Kafka consumer: %w", err)
+			return fmt.Errorf("failed to close Kafka consumer group: %w", err)
 		}
 	}
 	return nil
 }
 
+// Consume держит сессию consumer group, после ребалансировки Group.Consume возвращается и вызывается заново
 func (c *ImageConsumer) Consume(ctx context.Context) {
-	consumer, err := c.Consumer.ConsumePartition(c.Cfg.Topic, 0, sarama.OffsetOldest)
-	if err != nil {
-		c.Logger.Error(fmt.Sprintf("failed to consume partition: %w", err))
-		return
+	go func() {
+		for err := range c.Group.Errors() {
+			c.Logger.Error(fmt.Sprintf("Consumer group error: %v", err))
+		}
+	}()
+
+	c.Logger.Info(fmt.Sprintf("Consumer group %s started on topic %s", c.Cfg.GroupID, c.Cfg.Topic))
+
+	delay := _sessionRetryDelay
+	for {
+		err := c.Group.Consume(ctx, []string{c.Cfg.Topic}, c)
+		if ctx.Err() != nil {
+			c.Logger.Info("Consumer group stopped")
+			return
+		}
+		if err == nil {
+			// обычная ребалансировка, новая сессия сразу
+			delay = _sessionRetryDelay
+			continue
+		}
+		if errors.Is(err, sarama.ErrClosedConsumerGroup) {
+			return
+		}
+
+		c.Logger.Error(fmt.Sprintf("Consumer group session error, retry in %s: %v", delay, err))
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			c.Logger.Info("Consumer group stopped")
+			return
+		}
+		delay *= 2
+		if delay > _maxSessionRetryDelay {
+			delay = _maxSessionRetryDelay
+		}
 	}
+}
 
-	c.Logger.Info("Consumer started")
+func (c *ImageConsumer) Setup(session sarama.ConsumerGroupSession) error {
+	c.Logger.Info(fmt.Sprintf("Consumer group session started, claims: %v", session.Claims()))
+	return nil
+}
 
-	doneCh := make(chan struct{})
-	go func() {
-		for {
-			select {
-			case err := <-consumer.Errors():
-				c.Logger.Error(fmt.Sprintf("Consumer error: %w", err))
-				doneCh <- struct{}{}
-			case msg := <-consumer.Messages():
-				imgKafka, err := extractImageInfo(msg.Value, c.Logger)
-				if err != nil {
-					c.Logger.Error(fmt.Sprintf("Failed to extract image info from Kafka message: %v", err))
-					continue
+func (c *ImageConsumer) Cleanup(session sarama.ConsumerGroupSession) error {
+	c.Logger.Info(fmt.Sprintf("Consumer group session ended, claims: %v", session.Claims()))
+	return nil
+}
+
+// ConsumeClaim обрабатывает сообщения одной партиции. Offset помечается только после обработки
+// или отправки в dead-letter topic (карантин), коммит делает sarama (autocommit и при закрытии сессии)
+func (c *ImageConsumer) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	for {
+		select {
+		case msg, ok := <-claim.Messages():
+			if !ok {
+				return nil
+			}
+
+			ctx := session.Context()
+			attempts, err := c.handleMessage(ctx, msg)
+			if err != nil {
+				if ctx.Err() != nil {
+					// ребалансировка или остановка - сообщение получит следующий владелец партиции
+					return nil
 				}
-				c.Processor.ProcessImage(ctx, imgKafka)
 
+				if errors.Is(err, ErrUnsupportedJobVersion) {
+					err = c.sendDeadLetter(ctx, c.Quarantine, c.Cfg.QuarantineTopic, msg, attempts, err)
+				} else {
+					err = c.sendDeadLetter(ctx, c.DeadLetters, c.Cfg.DeadLetterTopic, msg, attempts, err)
+				}
+				if err != nil {
+					// без коммита offset сообщение будет перечитано в новой сессии
+					return err
+				}
 			}
+
+			session.MarkMessage(msg, "")
+		case <-session.Context().Done():
+			return nil
 		}
-	}()
+	}
+}
 
-	<-doneCh
+func (c *ImageConsumer) handleMessage(ctx context.Context, msg *sarama.ConsumerMessage) (int, error) {
+	job, err := DecodeImageJob(msg.Value)
+	if err != nil {
+		c.Logger.Error(fmt.Sprintf("Failed to decode image job from Kafka message %s/%d/%d: %v",
+			msg.Topic, msg.Partition, msg.Offset, err))
+		return 1, err
+	}
+	c.Logger.Info(fmt.Sprintf("Image job %s v%d, tenant %q, traceparent %q", job.ImageID, job.Version, job.Tenant,
+		job.TraceParent))
+
+	attempts, err := c.processWithRetry(ctx, job)
+	if err != nil && ctx.Err() == nil {
+		reason := fmt.Sprintf("failed after %d attempts: %v", attempts, err)
+		errStatus := c.Sink.UpdateImageStatus(ctx, job.ImageID, domain.StatusFailed, reason)
+		if errStatus != nil {
+			c.Logger.Error(fmt.Sprintf("Failed to mark image %s as failed: %v", job.ImageID, errStatus))
+		}
+	}
+
+	return attempts, err
+}
+
+// processWithRetry повторяет обработку с экспоненциальной задержкой до Cfg.MaxAttempts попыток
+func (c *ImageConsumer) processWithRetry(ctx context.Context, job ImageJob) (int, error) {
+	maxAttempts := c.Cfg.MaxAttempts
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+	backoff := c.Cfg.RetryBackoff
+
+	for attempt := 1; ; attempt++ {
+		err := c.handleImage(ctx, job)
+		if err == nil || attempt >= maxAttempts {
+			return attempt, err
+		}
+
+		c.Logger.Warn(fmt.Sprintf("Attempt %d/%d for image %s failed, retry in %s: %v",
+			attempt, maxAttempts, job.ImageID, backoff, err))
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return attempt, ctx.Err()
+		}
+
+		backoff *= 2
+		if c.Cfg.MaxRetryBackoff > 0 && backoff > c.Cfg.MaxRetryBackoff {
+			backoff = c.Cfg.MaxRetryBackoff
+		}
+	}
+}
+
+func (c *ImageConsumer) handleImage(ctx context.Context, job ImageJob) error {
+	err := c.Sink.UpdateImageStatus(ctx, job.ImageID, domain.StatusProcessing, "")
+	if err != nil {
+		c.Logger.Warn(fmt.Sprintf("Failed to mark image %s as processing: %v", job.ImageID, err))
+	}
+
+	imgDescriptor, err := c.Processor.ProcessImage(ctx, job)
+	if err != nil {
+		return fmt.Errorf("failed to process image %s: %w", job.ImageID, err)
+	}
+
+	err = c.Sink.UpdateImage(ctx, imgDescriptor, job.Presets)
+	if errors.Is(err, db.ErrStatusTransition) {
+		// результат устарел: изображение уже failed или снова в очереди, повтор ничего не изменит
+		c.Logger.Warn(fmt.Sprintf("Skip stale result for image %s: %v", imgDescriptor.ID, err))
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save processed image %s: %w", imgDescriptor.ID, err)
+	}
+
+	return nil
 }
 
-func extractImageInfo(messageValue []byte, logger logger.Interface) (ImgKafka, error) {
-	var data map[string]string
-	var imgKafka ImgKafka
-	err := json.Unmarshal(messageValue, &data)
+func (c *ImageConsumer) sendDeadLetter(ctx context.Context, producer MessageProducer, topic string,
+	msg *sarama.ConsumerMessage, attempts int, cause error) error {
+	deadLetter := DeadLetter{
+		Payload:   msg.Value,
+		Error:     cause.Error(),
+		Attempts:  attempts,
+		Topic:     msg.Topic,
+		Partition: msg.Partition,
+		Offset:    msg.Offset,
+		FailedAt:  time.Now().UTC(),
+	}
+
+	message, err := json.Marshal(deadLetter)
+	if err != nil {
+		return fmt.Errorf("failed to marshal dead letter: %w", err)
+	}
 
+	err = producer.ProduceMessage(ctx, message)
 	if err != nil {
-		return imgKafka, err
+		c.Logger.Error(fmt.Sprintf("Failed to send message %s/%d/%d to %s: %v",
+			msg.Topic, msg.Partition, msg.Offset, topic, err))
+		return fmt.Errorf("failed to send dead letter to %s: %w", topic, err)
 	}
 
-	imgKafka.ID = data["imageID"]
-	imgKafka.Name = data["name"]
-	imgKafka.OriginalURL = data["originUrl"]
-	return imgKafka, nil
+	c.Logger.Warn(fmt.Sprintf("Message %s/%d/%d moved to %s after %d attempts: %v",
+		msg.Topic, msg.Partition, msg.Offset, topic, attempts, cause))
+	return nil
 }
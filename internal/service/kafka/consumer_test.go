@@ -0,0 +1,116 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/Shopify/sarama"
+	"github.com/menyasosali/mts/config"
+	"github.com/menyasosali/mts/internal/domain"
+	"github.com/menyasosali/mts/pkg/logger"
+	"testing"
+)
+
+type fakeProducer struct {
+	messages [][]byte
+}
+
+func (p *fakeProducer) ProduceMessage(_ context.Context, message []byte) error {
+	p.messages = append(p.messages, message)
+	return nil
+}
+
+type fakeProcessor struct {
+	processed []string
+}
+
+func (p *fakeProcessor) ProcessImage(_ context.Context, job ImageJob) (domain.ImgDescriptor, error) {
+	p.processed = append(p.processed, job.ImageID)
+	return domain.ImgDescriptor{ID: job.ImageID}, nil
+}
+
+type fakeSink struct{}
+
+func (fakeSink) UpdateImage(context.Context, domain.ImgDescriptor, []string) error { return nil }
+
+func (fakeSink) UpdateImageStatus(context.Context, string, domain.ImageStatus, string) error {
+	return nil
+}
+
+type fakeSession struct {
+	sarama.ConsumerGroupSession
+	ctx    context.Context
+	marked []int64
+}
+
+func (s *fakeSession) Context() context.Context { return s.ctx }
+
+func (s *fakeSession) MarkMessage(msg *sarama.ConsumerMessage, _ string) {
+	s.marked = append(s.marked, msg.Offset)
+}
+
+type fakeClaim struct {
+	sarama.ConsumerGroupClaim
+	messages chan *sarama.ConsumerMessage
+}
+
+func (c *fakeClaim) Messages() <-chan *sarama.ConsumerMessage { return c.messages }
+
+func TestConsumeClaimQuarantinesUnsupportedVersions(t *testing.T) {
+	payloads := [][]byte{
+		[]byte(`{"imageID":"id-0","name":"cat.jpg"}`),
+		[]byte(`{"version":1,"imageID":"id-1","objectKey":"originals/abc"}`),
+		[]byte(`{"version":3,"imageID":"id-3","objectKey":"originals/abc"}`),
+	}
+
+	claim := &fakeClaim{messages: make(chan *sarama.ConsumerMessage, len(payloads))}
+	for i, payload := range payloads {
+		claim.messages <- &sarama.ConsumerMessage{Topic: "images", Offset: int64(i), Value: payload}
+	}
+	close(claim.messages)
+
+	processor := &fakeProcessor{}
+	deadLetters, quarantine := &fakeProducer{}, &fakeProducer{}
+	consumer := &ImageConsumer{
+		Logger:      logger.NewLogger("error"),
+		Processor:   processor,
+		Sink:        fakeSink{},
+		DeadLetters: deadLetters,
+		Quarantine:  quarantine,
+		Cfg: config.KafkaConfig{
+			Topic:           "images",
+			DeadLetterTopic: "images.dlq",
+			QuarantineTopic: "images.quarantine",
+			MaxAttempts:     1,
+		},
+	}
+	session := &fakeSession{ctx: context.Background()}
+
+	err := consumer.ConsumeClaim(session, claim)
+	if err != nil {
+		t.Fatalf("ConsumeClaim: %v", err)
+	}
+
+	if len(processor.processed) != 2 || processor.processed[0] != "id-0" || processor.processed[1] != "id-1" {
+		t.Errorf("processed %v, want [id-0 id-1]", processor.processed)
+	}
+	if len(deadLetters.messages) != 0 {
+		t.Errorf("dead letter topic got %d messages, want 0", len(deadLetters.messages))
+	}
+	if len(quarantine.messages) != 1 {
+		t.Fatalf("quarantine topic got %d messages, want 1", len(quarantine.messages))
+	}
+
+	var deadLetter DeadLetter
+	err = json.Unmarshal(quarantine.messages[0], &deadLetter)
+	if err != nil {
+		t.Fatalf("unmarshal quarantined message: %v", err)
+	}
+	if string(deadLetter.Payload) != string(payloads[2]) || deadLetter.Offset != 2 {
+		t.Errorf("quarantined %s at offset %d, want %s at offset 2", deadLetter.Payload, deadLetter.Offset,
+			payloads[2])
+	}
+
+	if len(session.marked) != len(payloads) {
+		t.Errorf("marked offsets %v, want all %d messages", session.marked, len(payloads))
+	}
+}
@@ -0,0 +1,22 @@
+package kafka
+
+import (
+	"context"
+	"time"
+)
+
+// MessageProducer отправляет сообщение в свой topic (ImageProducer)
+type MessageProducer interface {
+	ProduceMessage(context.Context, []byte) error
+}
+
+// DeadLetter - сообщение, которое не удалось обработать за Cfg.MaxAttempts попыток
+type DeadLetter struct {
+	Payload   []byte    `json:"payload"`
+	Error     string    `json:"error"`
+	Attempts  int       `json:"attempts"`
+	Topic     string    `json:"topic"`
+	Partition int32     `json:"partition"`
+	Offset    int64     `json:"offset"`
+	FailedAt  time.Time `json:"failedAt"`
+}
@@ -1,7 +0,0 @@
-package kafka
-
-type ImgKafka struct {
-	ID          string `json:"imageID"`
-	Name        string `json:"name"`
-	OriginalURL string `json:"originalUrl"`
-}
@@ -0,0 +1,170 @@
+package kafka
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/menyasosali/mts/internal/domain"
+	pb "github.com/menyasosali/mts/pkg/gen"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"time"
+)
+
+// ImageJobVersion - major версия схемы задачи worker'а (proto/image_job.proto). Версии 0 (без поля
+// version) и 1 - JSON-сообщения gateway до перехода на protobuf (schema/image_job.schema.json),
+// они принимаются на время миграции
+const ImageJobVersion = 2
+
+// ErrUnsupportedJobVersion - задача неизвестной версии, такие сообщения уходят в карантин
+var ErrUnsupportedJobVersion = errors.New("unsupported image job version")
+
+// ImageJob - задача на нарезку превью, одна для gateway (producer) и ImageConsumer.
+// ObjectKey - ключ оригинала в MinIO, worker скачивает его по нему
+type ImageJob struct {
+	Version     int
+	ImageID     string
+	Name        string
+	ObjectKey   string
+	OriginalURL string
+	SHA256      string
+	Presets     []string // пустой - все пресеты
+	Tenant      string
+	TraceParent string
+	TraceState  string
+	CreatedAt   time.Time
+}
+
+// legacyImageJob - JSON-задача версий 0 и 1
+type legacyImageJob struct {
+	Version     int    `json:"version"`
+	ImageID     string `json:"imageID"`
+	Name        string `json:"name"`
+	ObjectKey   string `json:"objectKey"`
+	OriginalURL string `json:"originalUrl"`
+}
+
+func NewImageJob(img domain.ImgDescriptor) ImageJob {
+	return ImageJob{
+		Version:     ImageJobVersion,
+		ImageID:     img.ID,
+		Name:        img.Name,
+		ObjectKey:   img.ObjectKey,
+		OriginalURL: img.URL,
+		SHA256:      img.SHA256,
+		CreatedAt:   time.Now().UTC(),
+	}
+}
+
+func (j ImageJob) Marshal() ([]byte, error) {
+	err := j.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	msg := &pb.ImageJob{
+		Version:     uint32(j.Version),
+		ImageId:     j.ImageID,
+		Name:        j.Name,
+		ObjectKey:   j.ObjectKey,
+		OriginalUrl: j.OriginalURL,
+		Sha256:      j.SHA256,
+		Presets:     j.Presets,
+		Tenant:      j.Tenant,
+		CreatedAt:   timestamppb.New(j.CreatedAt),
+	}
+	if j.TraceParent != "" {
+		msg.Trace = &pb.TraceContext{Traceparent: j.TraceParent, Tracestate: j.TraceState}
+	}
+
+	data, err := proto.Marshal(msg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal image job: %w", err)
+	}
+	return data, nil
+}
+
+// DecodeImageJob разбирает задачу в protobuf или в старом JSON. JSON отличается по первому байту:
+// protobuf-сообщение ImageJob не может начинаться с '{'
+func DecodeImageJob(data []byte) (ImageJob, error) {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		return decodeLegacyImageJob(trimmed)
+	}
+
+	var msg pb.ImageJob
+	err := proto.Unmarshal(data, &msg)
+	if err != nil {
+		return ImageJob{}, fmt.Errorf("failed to unmarshal image job: %w", err)
+	}
+
+	job := ImageJob{
+		Version:     int(msg.GetVersion()),
+		ImageID:     msg.GetImageId(),
+		Name:        msg.GetName(),
+		ObjectKey:   msg.GetObjectKey(),
+		OriginalURL: msg.GetOriginalUrl(),
+		SHA256:      msg.GetSha256(),
+		Presets:     msg.GetPresets(),
+		Tenant:      msg.GetTenant(),
+		TraceParent: msg.GetTrace().GetTraceparent(),
+		TraceState:  msg.GetTrace().GetTracestate(),
+	}
+	if msg.GetCreatedAt() != nil {
+		job.CreatedAt = msg.GetCreatedAt().AsTime()
+	}
+
+	if job.Version != ImageJobVersion {
+		return ImageJob{}, fmt.Errorf("protobuf version %d: %w", job.Version, ErrUnsupportedJobVersion)
+	}
+
+	err = job.validate()
+	if err != nil {
+		return ImageJob{}, err
+	}
+	return job, nil
+}
+
+// decodeLegacyImageJob - сообщения без версии от gateway до введения схемы не содержат objectKey,
+// оригинал тогда лежал в MinIO под именем файла
+func decodeLegacyImageJob(data []byte) (ImageJob, error) {
+	var legacy legacyImageJob
+	err := json.Unmarshal(data, &legacy)
+	if err != nil {
+		return ImageJob{}, fmt.Errorf("failed to unmarshal legacy image job: %w", err)
+	}
+
+	if legacy.Version > 1 {
+		return ImageJob{}, fmt.Errorf("JSON version %d: %w", legacy.Version, ErrUnsupportedJobVersion)
+	}
+	if legacy.Version == 0 && legacy.ObjectKey == "" {
+		legacy.ObjectKey = legacy.Name
+	}
+
+	job := ImageJob{
+		Version:     legacy.Version,
+		ImageID:     legacy.ImageID,
+		Name:        legacy.Name,
+		ObjectKey:   legacy.ObjectKey,
+		OriginalURL: legacy.OriginalURL,
+	}
+
+	err = job.validate()
+	if err != nil {
+		return ImageJob{}, err
+	}
+	return job, nil
+}
+
+func (j ImageJob) validate() error {
+	if j.Version < 0 || j.Version > ImageJobVersion {
+		return fmt.Errorf("version %d: %w", j.Version, ErrUnsupportedJobVersion)
+	}
+	if j.ImageID == "" {
+		return errors.New("image job: imageID is required")
+	}
+	if j.ObjectKey == "" {
+		return errors.New("image job: objectKey is required")
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package kafka
+
+import (
+	"errors"
+	pb "github.com/menyasosali/mts/pkg/gen"
+	"google.golang.org/protobuf/proto"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestImageJobRoundTrip(t *testing.T) {
+	job := ImageJob{
+		Version:     ImageJobVersion,
+		ImageID:     "0b6c4a0e-5d0e-4a39-9d55-3b1f5c2f7d11",
+		Name:        "cat.jpg",
+		ObjectKey:   "originals/5e884898da28047151d0e56f8dc6292773603d0d6aabbdd62a11ef721d1542d8",
+		OriginalURL: "http://minio:9000/images/originals/5e884898",
+		SHA256:      "5e884898da28047151d0e56f8dc6292773603d0d6aabbdd62a11ef721d1542d8",
+		Presets:     []string{"thumb", "card"},
+		Tenant:      "acme",
+		TraceParent: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		TraceState:  "vendor=value",
+		CreatedAt:   time.Date(2024, 5, 1, 12, 30, 0, 123000000, time.UTC),
+	}
+
+	data, err := job.Marshal()
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	decoded, err := DecodeImageJob(data)
+	if err != nil {
+		t.Fatalf("DecodeImageJob: %v", err)
+	}
+
+	if !reflect.DeepEqual(decoded, job) {
+		t.Errorf("round trip mismatch:\n got %+v\nwant %+v", decoded, job)
+	}
+}
+
+func TestDecodeLegacyImageJob(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want ImageJob
+	}{
+		{
+			name: "v1",
+			data: `{"version":1,"imageID":"id-1","name":"cat.jpg","objectKey":"originals/abc",` +
+				`"originalUrl":"http://minio/originals/abc"}`,
+			want: ImageJob{
+				Version:     1,
+				ImageID:     "id-1",
+				Name:        "cat.jpg",
+				ObjectKey:   "originals/abc",
+				OriginalURL: "http://minio/originals/abc",
+			},
+		},
+		{
+			name: "v0 without object key",
+			data: ` {"imageID":"id-0","name":"cat.jpg","originalUrl":"http://minio/cat.jpg"}`,
+			want: ImageJob{
+				Version:     0,
+				ImageID:     "id-0",
+				Name:        "cat.jpg",
+				ObjectKey:   "cat.jpg",
+				OriginalURL: "http://minio/cat.jpg",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job, err := DecodeImageJob([]byte(tt.data))
+			if err != nil {
+				t.Fatalf("DecodeImageJob: %v", err)
+			}
+			if !reflect.DeepEqual(job, tt.want) {
+				t.Errorf("got %+v, want %+v", job, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeImageJobRejectsBadPayload(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"broken JSON", []byte(`{"imageID":`)},
+		{"broken protobuf", []byte{0x0a, 0xff, 0x01}},
+		{"JSON without image ID", []byte(`{"version":1,"objectKey":"originals/abc"}`)},
+		{"v1 without object key", []byte(`{"version":1,"imageID":"id-1"}`)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := DecodeImageJob(tt.data)
+			if err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestDecodeImageJobVersions(t *testing.T) {
+	future, err := proto.Marshal(&pb.ImageJob{Version: ImageJobVersion + 1, ImageId: "id-3", ObjectKey: "originals/abc"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	unversioned, err := proto.Marshal(&pb.ImageJob{ImageId: "id-p0", ObjectKey: "originals/abc"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		data        []byte
+		unsupported bool
+	}{
+		{"JSON v0", []byte(`{"imageID":"id-0","name":"cat.jpg"}`), false},
+		{"JSON v1", []byte(`{"version":1,"imageID":"id-1","objectKey":"originals/abc"}`), false},
+		{"JSON v2", []byte(`{"version":2,"imageID":"id-2","objectKey":"originals/abc"}`), true},
+		{"JSON negative version", []byte(`{"version":-1,"imageID":"id-2","objectKey":"originals/abc"}`), true},
+		{"protobuf next version", future, true},
+		{"protobuf without version", unversioned, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := DecodeImageJob(tt.data)
+			switch {
+			case tt.unsupported && !errors.Is(err, ErrUnsupportedJobVersion):
+				t.Errorf("got %v, want ErrUnsupportedJobVersion", err)
+			case !tt.unsupported && err != nil:
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
@@ -19,13 +19,13 @@ func NewImageProducer(logger logger.Interface, cfg config.KafkaConfig) (*ImagePr
 	config.Producer.Return.Successes = true
 	config.Producer.RequiredAcks = sarama.WaitForAll
 	config.Producer.Retry.Max = 5
-	logger.Info(fmt.Sprintf("Kafka producer SARAMA config - producer.go - 20: %s", config))
+	logger.Info(fmt.Sprintf("Kafka producer SARAMA config - producer.go - 20: %+v", config))
 
 	producer, err := sarama.NewSyncProducer(cfg.Brokers, config)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Kafka producer: %w", err)
 	}
-	logger.Info(fmt.Sprintf("Kafka producer moi config - producer.go - 25: %s", cfg))
+	logger.Info(fmt.Sprintf("Kafka producer moi config - producer.go - 25: %+v", cfg))
 
 	imageProducer := &ImageProducer{
 		Logger:   logger,
@@ -37,10 +37,18 @@ func NewImageProducer(logger logger.Interface, cfg config.KafkaConfig) (*ImagePr
 }
 
 func (p *ImageProducer) ProduceMessage(ctx context.Context, message []byte) error {
+	return p.SendMessage(ctx, p.Cfg.Topic, "", message)
+}
+
+// SendMessage отправляет сообщение в произвольный topic, key пустой - партиция выбирается producer'ом
+func (p *ImageProducer) SendMessage(ctx context.Context, topic, key string, message []byte) error {
 	messageInput := &sarama.ProducerMessage{
-		Topic: p.Cfg.Topic,
+		Topic: topic,
 		Value: sarama.ByteEncoder(message),
 	}
+	if key != "" {
+		messageInput.Key = sarama.StringEncoder(key)
+	}
 
 	partition, offset, err := p.Producer.SendMessage(messageInput)
 	if err != nil {
@@ -48,7 +56,7 @@ func (p *ImageProducer) ProduceMessage(ctx context.Context, message []byte) erro
 		return err
 	}
 
-	p.Logger.Info(fmt.Sprintf("Message is stored in topic(%s)/partition(%d)/offset(%d)\n", p.Cfg.Topic, partition, offset))
+	p.Logger.Info(fmt.Sprintf("Message is stored in topic(%s)/partition(%d)/offset(%d)\n", topic, partition, offset))
 	return nil
 }
 
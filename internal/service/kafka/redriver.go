@@ -0,0 +1,159 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/Shopify/sarama"
+	"github.com/menyasosali/mts/config"
+	"github.com/menyasosali/mts/pkg/logger"
+	"sync"
+)
+
+// Redriver перекладывает сообщения из dead-letter topic (или карантина) обратно в основной topic.
+// Читает Source своей consumer group до high watermark каждой партиции и завершается
+type Redriver struct {
+	Logger   logger.Interface
+	Producer MessageProducer
+	Group    sarama.ConsumerGroup
+	Cfg      config.KafkaConfig
+	Source   string
+	DryRun   bool
+
+	mu        sync.Mutex
+	pending   int
+	redriven  int
+	stopGroup context.CancelFunc
+}
+
+var _ sarama.ConsumerGroupHandler = (*Redriver)(nil)
+
+func NewRedriver(logger logger.Interface, producer MessageProducer, cfg config.KafkaConfig, source string,
+	dryRun bool) (*Redriver, error) {
+	config := sarama.NewConfig()
+	config.Consumer.Offsets.Initial = sarama.OffsetOldest
+
+	group, err := sarama.NewConsumerGroup(cfg.Brokers, cfg.GroupID+"-redrive", config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Kafka redrive consumer group: %w", err)
+	}
+
+	redriver := &Redriver{
+		Logger:   logger,
+		Producer: producer,
+		Group:    group,
+		Cfg:      cfg,
+		Source:   source,
+		DryRun:   dryRun,
+	}
+
+	return redriver, nil
+}
+
+// Redrive возвращает количество переотправленных сообщений
+func (r *Redriver) Redrive(ctx context.Context) (int, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	r.stopGroup = cancel
+
+	err := r.Group.Consume(ctx, []string{r.Source}, r)
+	if err != nil && !errors.Is(err, context.Canceled) {
+		return r.redriven, fmt.Errorf("failed to consume %s: %w", r.Source, err)
+	}
+
+	return r.redriven, nil
+}
+
+func (r *Redriver) Close() error {
+	if r.Group != nil {
+		err := r.Group.Close()
+		if err != nil {
+			return fmt.Errorf("failed to close Kafka redrive consumer group: %w", err)
+		}
+	}
+	return nil
+}
+
+func (r *Redriver) Setup(session sarama.ConsumerGroupSession) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.pending = len(session.Claims()[r.Source])
+	if r.pending == 0 {
+		r.stopGroup()
+	}
+	return nil
+}
+
+func (r *Redriver) Cleanup(sarama.ConsumerGroupSession) error {
+	return nil
+}
+
+// ConsumeClaim не выходит сам: sarama завершает сессию целиком, как только любой claim вернулся,
+// поэтому дочитанная партиция ждет остальные, а последняя останавливает группу
+func (r *Redriver) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	if claim.InitialOffset() >= claim.HighWaterMarkOffset() {
+		r.partitionDone()
+	}
+
+	for {
+		select {
+		case msg, ok := <-claim.Messages():
+			if !ok {
+				return nil
+			}
+
+			err := r.redrive(session.Context(), msg)
+			if err != nil {
+				return err
+			}
+
+			if !r.DryRun {
+				session.MarkMessage(msg, "")
+			}
+
+			if msg.Offset+1 >= claim.HighWaterMarkOffset() {
+				r.partitionDone()
+			}
+		case <-session.Context().Done():
+			return nil
+		}
+	}
+}
+
+func (r *Redriver) redrive(ctx context.Context, msg *sarama.ConsumerMessage) error {
+	var deadLetter DeadLetter
+	err := json.Unmarshal(msg.Value, &deadLetter)
+	if err != nil {
+		r.Logger.Error(fmt.Sprintf("Skip malformed dead letter %s/%d/%d: %v", msg.Topic, msg.Partition, msg.Offset, err))
+		return nil
+	}
+
+	r.Logger.Info(fmt.Sprintf("Dead letter %s/%d/%d (from %s/%d/%d, %d attempts): %s",
+		msg.Topic, msg.Partition, msg.Offset, deadLetter.Topic, deadLetter.Partition, deadLetter.Offset,
+		deadLetter.Attempts, deadLetter.Error))
+
+	if !r.DryRun {
+		err = r.Producer.ProduceMessage(ctx, deadLetter.Payload)
+		if err != nil {
+			return fmt.Errorf("failed to redrive message %s/%d/%d: %w", msg.Topic, msg.Partition, msg.Offset, err)
+		}
+	}
+
+	r.mu.Lock()
+	r.redriven++
+	r.mu.Unlock()
+
+	return nil
+}
+
+func (r *Redriver) partitionDone() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.pending--
+	if r.pending <= 0 {
+		r.stopGroup()
+	}
+}
@@ -1,8 +1,8 @@
 package minio
 
 import (
-	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"github.com/menyasosali/mts/config"
 	"github.com/menyasosali/mts/pkg/logger"
@@ -10,19 +10,30 @@ import (
 	"github.com/minio/minio-go/v7/pkg/credentials"
 	"io"
 	"mime"
+	"net/http"
 	"net/url"
 	"path/filepath"
+	"strconv"
+	"time"
 )
 
 // interface для minio
 
 type InterfaceMinio interface {
-	UploadFile(context.Context, io.Reader, string) (string, error)
-	GetFileURL(context.Context, string) (string, error)
+	UploadFile(context.Context, io.Reader, int64, string, string) (string, error)
+	GetObjectURL(context.Context, string) (string, error)
 	DownloadFile(context.Context, string) ([]byte, error)
 	DeleteFile(context.Context, string) error
 }
 
+// _partSize - размер части multipart-загрузки при неизвестном размере файла. Без него minio-go
+// рассчитывает часть под максимальный объект в 5 TiB и выделяет буфер ~550 MiB
+const _partSize = 16 << 20
+
+var _ InterfaceMinio = (*ClientMinio)(nil)
+
+var ErrFileNotFound = errors.New("file not found in MinIO")
+
 type ClientMinio struct {
 	Logger     logger.Interface
 	Client     *minio.Client
@@ -48,8 +59,31 @@ func NewMinioClient(logger logger.Interface, cfg config.MinioConfig) (*ClientMin
 	return minioClient, nil
 }
 
-func (c *ClientMinio) UploadFile(ctx context.Context, file []byte, filename string) (string, error) {
-	contentType := mime.TypeByExtension(filepath.Ext(filename))
+// UploadFile потоково сохраняет объект. size = -1, если размер неизвестен - тогда загрузка идет
+// частями по _partSize. contentType попадает в метаданные; пустой - определяется по расширению
+func (c *ClientMinio) UploadFile(ctx context.Context, file io.Reader, size int64, filename, contentType string,
+) (string, error) {
+	if contentType == "" {
+		contentType = mime.TypeByExtension(filepath.Ext(filename))
+	}
+	c.ensureBucket(ctx)
+
+	_, err := c.Client.PutObject(ctx, c.BucketName, filename, file, size, minio.PutObjectOptions{
+		ContentType: contentType,
+		PartSize:    _partSize,
+	})
+	if err != nil {
+		c.Logger.Error(fmt.Sprintf("Failed to upload file to MinIO: %v", err))
+		return "", fmt.Errorf("failed to upload file to MinIO: %w", err)
+	}
+
+	fileURL, _ := c.GetObjectURL(ctx, filename)
+
+	return fileURL, nil
+}
+
+// ensureBucket создает bucket при первой загрузке
+func (c *ClientMinio) ensureBucket(ctx context.Context) {
 	location := "serv"
 
 	err := c.Client.MakeBucket(ctx, c.BucketName, minio.MakeBucketOptions{Region: location})
@@ -64,18 +98,6 @@ func (c *ClientMinio) UploadFile(ctx context.Context, file []byte, filename stri
 	} else {
 		c.Logger.Info(fmt.Sprintf("Successfully created %s\n", c.BucketName))
 	}
-
-	_, err = c.Client.PutObject(ctx, c.BucketName, filename, bytes.NewReader(file), -1, minio.PutObjectOptions{
-		ContentType: contentType,
-	})
-	if err != nil {
-		c.Logger.Error(fmt.Sprintf("Failed to upload file to MinIO: %v", err))
-		return "", fmt.Errorf("failed to upload file to MinIO: %w", err)
-	}
-
-	fileURL, _ := c.GetObjectURL(ctx, filename)
-
-	return fileURL, nil
 }
 
 func (c *ClientMinio) DownloadFile(ctx context.Context, filename string) ([]byte, error) {
@@ -93,6 +115,69 @@ func (c *ClientMinio) DownloadFile(ctx context.Context, filename string) ([]byte
 	return data, nil
 }
 
+// OpenFile открывает объект для потокового чтения, вызывающий закрывает *minio.Object
+func (c *ClientMinio) OpenFile(ctx context.Context, filename string) (*minio.Object, minio.ObjectInfo, error) {
+	object, err := c.Client.GetObject(ctx, c.BucketName, filename, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, minio.ObjectInfo{}, fmt.Errorf("failed to open file in MinIO: %w", err)
+	}
+
+	info, err := object.Stat()
+	if err != nil {
+		object.Close()
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return nil, minio.ObjectInfo{}, fmt.Errorf("%s: %w", filename, ErrFileNotFound)
+		}
+		return nil, minio.ObjectInfo{}, fmt.Errorf("failed to stat file in MinIO: %w", err)
+	}
+
+	return object, info, nil
+}
+
+// CopyFile копирует объект внутри bucket без передачи данных через сервис
+func (c *ClientMinio) CopyFile(ctx context.Context, src, dst string) error {
+	_, err := c.Client.CopyObject(ctx,
+		minio.CopyDestOptions{Bucket: c.BucketName, Object: dst},
+		minio.CopySrcOptions{Bucket: c.BucketName, Object: src})
+	if err != nil {
+		c.Logger.Error(fmt.Sprintf("Failed to copy file in MinIO: %v", err))
+		return fmt.Errorf("failed to copy file in MinIO: %w", err)
+	}
+
+	return nil
+}
+
+func (c *ClientMinio) StatFile(ctx context.Context, filename string) (minio.ObjectInfo, error) {
+	info, err := c.Client.StatObject(ctx, c.BucketName, filename, minio.StatObjectOptions{})
+	if err != nil {
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return minio.ObjectInfo{}, fmt.Errorf("%s: %w", filename, ErrFileNotFound)
+		}
+		return minio.ObjectInfo{}, fmt.Errorf("failed to stat file in MinIO: %w", err)
+	}
+
+	return info, nil
+}
+
+// PresignUpload возвращает url для загрузки объекта PUT-запросом напрямую в MinIO. Content-Type и
+// Content-Length входят в подпись: объект другого типа или размера MinIO не примет
+func (c *ClientMinio) PresignUpload(ctx context.Context, filename, contentType string, size int64,
+	expires time.Duration) (string, error) {
+	c.ensureBucket(ctx)
+
+	headers := http.Header{}
+	headers.Set("Content-Type", contentType)
+	headers.Set("Content-Length", strconv.FormatInt(size, 10))
+
+	presignedURL, err := c.Client.PresignHeader(ctx, http.MethodPut, c.BucketName, filename, expires, nil, headers)
+	if err != nil {
+		c.Logger.Error(fmt.Sprintf("Failed to presign upload in MinIO: %v", err))
+		return "", fmt.Errorf("failed to presign upload in MinIO: %w", err)
+	}
+
+	return presignedURL.String(), nil
+}
+
 func (c *ClientMinio) GetObjectURL(ctx context.Context, filename string) (string, error) {
 	baseURL := c.Client.EndpointURL()
 
@@ -107,6 +192,12 @@ func (c *ClientMinio) GetObjectURL(ctx context.Context, filename string) (string
 	return objectURL, nil
 }
 
+// ListFiles перечисляет объекты бакета с префиксом prefix (пустой - все), ошибка листинга приходит
+// в ObjectInfo.Err. Канал закрывается по окончании листинга или отмене ctx
+func (c *ClientMinio) ListFiles(ctx context.Context, prefix string) <-chan minio.ObjectInfo {
+	return c.Client.ListObjects(ctx, c.BucketName, minio.ListObjectsOptions{Prefix: prefix, Recursive: true})
+}
+
 func (c *ClientMinio) DeleteFile(ctx context.Context, filename string) error {
 	err := c.Client.RemoveObject(ctx, c.BucketName, filename, minio.RemoveObjectOptions{})
 	if err != nil {
@@ -0,0 +1,92 @@
+package minio
+
+import (
+	"context"
+	"fmt"
+	"github.com/minio/minio-go/v7"
+	"io"
+	"mime"
+	"path/filepath"
+)
+
+// MinPartSize - минимальный размер части multipart-загрузки в S3, кроме последней
+const MinPartSize = 5 << 20
+
+// NewMultipartUpload начинает multipart-загрузку объекта и возвращает ее id в MinIO
+func (c *ClientMinio) NewMultipartUpload(ctx context.Context, filename, contentType string) (string, error) {
+	if contentType == "" {
+		contentType = mime.TypeByExtension(filepath.Ext(filename))
+	}
+	c.ensureBucket(ctx)
+
+	core := minio.Core{Client: c.Client}
+	uploadID, err := core.NewMultipartUpload(ctx, c.BucketName, filename, minio.PutObjectOptions{
+		ContentType: contentType,
+	})
+	if err != nil {
+		c.Logger.Error(fmt.Sprintf("Failed to start multipart upload in MinIO: %v", err))
+		return "", fmt.Errorf("failed to start multipart upload in MinIO: %w", err)
+	}
+
+	return uploadID, nil
+}
+
+// UploadPart загружает часть с номером partNumber (с 1). Все части, кроме последней,
+// должны быть не меньше MinPartSize
+func (c *ClientMinio) UploadPart(ctx context.Context, filename, uploadID string, partNumber int, data io.Reader,
+	size int64) error {
+	core := minio.Core{Client: c.Client}
+	_, err := core.PutObjectPart(ctx, c.BucketName, filename, uploadID, partNumber, data, size,
+		minio.PutObjectPartOptions{})
+	if err != nil {
+		c.Logger.Error(fmt.Sprintf("Failed to upload part %d to MinIO: %v", partNumber, err))
+		return fmt.Errorf("failed to upload part %d to MinIO: %w", partNumber, err)
+	}
+
+	return nil
+}
+
+// CompleteMultipartUpload собирает объект из частей 1..partCount и возвращает его url. Части с большими
+// номерами - остатки оборванных запросов, они в объект не попадают
+func (c *ClientMinio) CompleteMultipartUpload(ctx context.Context, filename, uploadID string, partCount int,
+) (string, error) {
+	core := minio.Core{Client: c.Client}
+
+	var parts []minio.CompletePart
+	marker := 0
+	for {
+		result, err := core.ListObjectParts(ctx, c.BucketName, filename, uploadID, marker, 1000)
+		if err != nil {
+			return "", fmt.Errorf("failed to list uploaded parts in MinIO: %w", err)
+		}
+		for _, part := range result.ObjectParts {
+			if part.PartNumber > partCount {
+				continue
+			}
+			parts = append(parts, minio.CompletePart{PartNumber: part.PartNumber, ETag: part.ETag})
+		}
+		if !result.IsTruncated {
+			break
+		}
+		marker = result.NextPartNumberMarker
+	}
+
+	_, err := core.CompleteMultipartUpload(ctx, c.BucketName, filename, uploadID, parts, minio.PutObjectOptions{})
+	if err != nil {
+		c.Logger.Error(fmt.Sprintf("Failed to complete multipart upload in MinIO: %v", err))
+		return "", fmt.Errorf("failed to complete multipart upload in MinIO: %w", err)
+	}
+
+	return c.GetObjectURL(ctx, filename)
+}
+
+func (c *ClientMinio) AbortMultipartUpload(ctx context.Context, filename, uploadID string) error {
+	core := minio.Core{Client: c.Client}
+	err := core.AbortMultipartUpload(ctx, c.BucketName, filename, uploadID)
+	if err != nil {
+		c.Logger.Error(fmt.Sprintf("Failed to abort multipart upload in MinIO: %v", err))
+		return fmt.Errorf("failed to abort multipart upload in MinIO: %w", err)
+	}
+
+	return nil
+}
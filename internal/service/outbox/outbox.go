@@ -0,0 +1,32 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"github.com/jackc/pgx/v4"
+)
+
+// Transactional outbox: сообщение для Kafka пишется в таблицу outbox в той же транзакции, что и
+// изменение данных, а Relay публикует его позже. Так сообщение не теряется, если Kafka недоступна
+// или процесс упал между записью в базу и отправкой
+
+type Message struct {
+	Topic   string
+	Key     string
+	Payload []byte
+}
+
+// Insert добавляет сообщение в outbox в транзакции вызывающего
+func Insert(ctx context.Context, tx pgx.Tx, msg Message) error {
+	query := `
+		INSERT INTO outbox (topic, message_key, payload)
+		VALUES ($1, $2, $3)
+	`
+
+	_, err := tx.Exec(ctx, query, msg.Topic, msg.Key, msg.Payload)
+	if err != nil {
+		return fmt.Errorf("failed to insert outbox message: %w", err)
+	}
+
+	return nil
+}
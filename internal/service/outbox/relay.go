@@ -0,0 +1,180 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"github.com/jackc/pgx/v4"
+	"github.com/menyasosali/mts/config"
+	"github.com/menyasosali/mts/pkg/logger"
+	"github.com/menyasosali/mts/pkg/postgres"
+	"time"
+)
+
+// Publisher отправляет сообщение в topic (kafka.ImageProducer)
+type Publisher interface {
+	SendMessage(ctx context.Context, topic, key string, message []byte) error
+}
+
+// Relay публикует неотправленные сообщения outbox. Строки берутся с FOR UPDATE SKIP LOCKED,
+// поэтому несколько экземпляров gateway не отправляют одно сообщение параллельно. Неудачная
+// отправка откладывается с экспоненциальной задержкой, порядок сообщений не гарантируется
+type Relay struct {
+	Logger    logger.Interface
+	Pg        *postgres.Postgres
+	Publisher Publisher
+	Cfg       config.OutboxConfig
+}
+
+func NewRelay(logger logger.Interface, pg *postgres.Postgres, publisher Publisher, cfg config.OutboxConfig) *Relay {
+	return &Relay{
+		Logger:    logger,
+		Pg:        pg,
+		Publisher: publisher,
+		Cfg:       cfg,
+	}
+}
+
+func (r *Relay) Start(ctx context.Context) {
+	r.Logger.Info("Outbox relay started")
+	go r.run(ctx)
+}
+
+func (r *Relay) run(ctx context.Context) {
+	poll := time.NewTicker(r.Cfg.PollInterval)
+	defer poll.Stop()
+	cleanup := time.NewTicker(time.Hour)
+	defer cleanup.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			r.Logger.Info("Outbox relay stopped")
+			return
+		case <-poll.C:
+			// пока батчи полные, сразу берем следующий
+			for {
+				sent, err := r.relayBatch(ctx)
+				if err != nil {
+					r.Logger.Error(fmt.Sprintf("Outbox relay error: %v", err))
+					break
+				}
+				if sent < r.Cfg.BatchSize {
+					break
+				}
+			}
+		case <-cleanup.C:
+			err := r.deleteSent(ctx)
+			if err != nil {
+				r.Logger.Error(fmt.Sprintf("Outbox cleanup error: %v", err))
+			}
+		}
+	}
+}
+
+type pendingMessage struct {
+	ID       int64
+	Attempts int
+	Message
+}
+
+// relayBatch отправляет до BatchSize сообщений и возвращает количество обработанных строк
+func (r *Relay) relayBatch(ctx context.Context) (int, error) {
+	selectPending := `
+		SELECT id, topic, message_key, payload, attempts
+		FROM outbox
+		WHERE sent_at IS NULL AND available_at <= now()
+		ORDER BY id
+		LIMIT $1
+		FOR UPDATE SKIP LOCKED
+	`
+	markSent := `
+		UPDATE outbox
+		SET sent_at = now(), attempts = attempts + 1, last_error = ''
+		WHERE id = $1
+	`
+	markFailed := `
+		UPDATE outbox
+		SET attempts = attempts + 1, last_error = $2, available_at = now() + $3 * interval '1 millisecond'
+		WHERE id = $1
+	`
+
+	tx, err := r.Pg.Pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	pending, err := selectMessages(ctx, tx, selectPending, r.Cfg.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, msg := range pending {
+		errSend := r.Publisher.SendMessage(ctx, msg.Topic, msg.Key, msg.Payload)
+		if errSend != nil {
+			backoff := r.backoff(msg.Attempts)
+			r.Logger.Warn(fmt.Sprintf("Failed to relay outbox message %d (attempt %d), retry in %s: %v",
+				msg.ID, msg.Attempts+1, backoff, errSend))
+			_, err = tx.Exec(ctx, markFailed, msg.ID, errSend.Error(), backoff.Milliseconds())
+		} else {
+			_, err = tx.Exec(ctx, markSent, msg.ID)
+		}
+		if err != nil {
+			return 0, fmt.Errorf("failed to update outbox message %d: %w", msg.ID, err)
+		}
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to commit outbox batch: %w", err)
+	}
+
+	return len(pending), nil
+}
+
+func selectMessages(ctx context.Context, tx pgx.Tx, query string, limit int) ([]pendingMessage, error) {
+	rows, err := tx.Query(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to select outbox messages: %w", err)
+	}
+	defer rows.Close()
+
+	var pending []pendingMessage
+	for rows.Next() {
+		var msg pendingMessage
+		err = rows.Scan(&msg.ID, &msg.Topic, &msg.Key, &msg.Payload, &msg.Attempts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan outbox message: %w", err)
+		}
+		pending = append(pending, msg)
+	}
+
+	return pending, rows.Err()
+}
+
+func (r *Relay) backoff(attempts int) time.Duration {
+	backoff := r.Cfg.RetryBackoff
+	for i := 0; i < attempts && backoff < r.Cfg.MaxRetryBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > r.Cfg.MaxRetryBackoff {
+		backoff = r.Cfg.MaxRetryBackoff
+	}
+	return backoff
+}
+
+// deleteSent удаляет отправленные сообщения старше Cfg.Retention
+func (r *Relay) deleteSent(ctx context.Context) error {
+	query := `
+		DELETE FROM outbox
+		WHERE sent_at < now() - $1 * interval '1 millisecond'
+	`
+
+	tag, err := r.Pg.Pool.Exec(ctx, query, r.Cfg.Retention.Milliseconds())
+	if err != nil {
+		return fmt.Errorf("failed to delete sent outbox messages: %w", err)
+	}
+
+	r.Logger.Info(fmt.Sprintf("Deleted %d sent outbox messages", tag.RowsAffected()))
+	return nil
+}
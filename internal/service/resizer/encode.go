@@ -0,0 +1,128 @@
+package resizer
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/gen2brain/avif"
+	"github.com/gen2brain/webp"
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"strings"
+)
+
+type Format string
+
+const (
+	// FormatOriginal - формат исходного файла
+	FormatOriginal Format = "original"
+	FormatJPEG     Format = "jpeg"
+	FormatPNG      Format = "png"
+	FormatGIF      Format = "gif"
+	FormatWebP     Format = "webp"
+	FormatAVIF     Format = "avif"
+)
+
+// EncodeOptions - настройки кодирования превью. Нулевое качество - значение кодека по умолчанию
+type EncodeOptions struct {
+	Quality        int
+	PNGCompression png.CompressionLevel
+}
+
+func ParseFormat(format string) (Format, error) {
+	switch Format(strings.ToLower(format)) {
+	case "", FormatOriginal:
+		return FormatOriginal, nil
+	case "jpg", FormatJPEG:
+		return FormatJPEG, nil
+	case FormatPNG:
+		return FormatPNG, nil
+	case FormatGIF:
+		return FormatGIF, nil
+	case FormatWebP:
+		return FormatWebP, nil
+	case FormatAVIF:
+		return FormatAVIF, nil
+	default:
+		return "", fmt.Errorf("unknown output format %q", format)
+	}
+}
+
+func ParsePNGCompression(level string) (png.CompressionLevel, error) {
+	switch strings.ToLower(level) {
+	case "", "default":
+		return png.DefaultCompression, nil
+	case "none":
+		return png.NoCompression, nil
+	case "fast":
+		return png.BestSpeed, nil
+	case "best":
+		return png.BestCompression, nil
+	default:
+		return 0, fmt.Errorf("unknown png compression level %q", level)
+	}
+}
+
+func (f Format) ContentType() string {
+	return "image/" + string(f)
+}
+
+func (f Format) Extension() string {
+	if f == FormatJPEG {
+		return ".jpg"
+	}
+	return "." + string(f)
+}
+
+// resolve подставляет формат исходника вместо original. sourceFormat - имя из image.Decode
+func (f Format) resolve(sourceFormat string) (Format, error) {
+	if f != FormatOriginal {
+		return f, nil
+	}
+
+	format, err := ParseFormat(sourceFormat)
+	if err != nil || format == FormatOriginal {
+		return "", fmt.Errorf("unsupported source format %q", sourceFormat)
+	}
+	return format, nil
+}
+
+func encode(img image.Image, format Format, opts EncodeOptions) ([]byte, error) {
+	var buf bytes.Buffer
+	var err error
+
+	switch format {
+	case FormatJPEG:
+		quality := jpeg.DefaultQuality
+		if opts.Quality > 0 {
+			quality = opts.Quality
+		}
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality})
+	case FormatPNG:
+		encoder := png.Encoder{CompressionLevel: opts.PNGCompression}
+		err = encoder.Encode(&buf, img)
+	case FormatGIF:
+		err = gif.Encode(&buf, img, nil)
+	case FormatWebP:
+		quality := webp.DefaultQuality
+		if opts.Quality > 0 {
+			quality = opts.Quality
+		}
+		err = webp.Encode(&buf, img, webp.Options{Quality: quality, Method: webp.DefaultMethod})
+	case FormatAVIF:
+		quality := avif.DefaultQuality
+		if opts.Quality > 0 {
+			quality = opts.Quality
+		}
+		err = avif.Encode(&buf, img, avif.Options{Quality: quality, QualityAlpha: quality, Speed: avif.DefaultSpeed,
+			ChromaSubsampling: image.YCbCrSubsampleRatio420})
+	default:
+		return nil, fmt.Errorf("unsupported output format %q", format)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s: %w", format, err)
+	}
+	return buf.Bytes(), nil
+}
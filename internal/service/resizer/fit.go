@@ -0,0 +1,194 @@
+package resizer
+
+import (
+	"fmt"
+	"github.com/menyasosali/mts/internal/domain"
+	"github.com/nfnt/resize"
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+	"strconv"
+	"strings"
+)
+
+type Fit string
+
+const (
+	// FitContain вписывает изображение в рамку с сохранением пропорций
+	FitContain Fit = "contain"
+	// FitCover заполняет рамку с сохранением пропорций и обрезает лишнее по центру
+	FitCover Fit = "cover"
+	// FitFill вписывает изображение и добивает до точного размера цветом фона
+	FitFill Fit = "fill"
+	// FitExact растягивает изображение до точного размера без сохранения пропорций
+	FitExact Fit = "exact"
+)
+
+func ParseFit(fit string) (Fit, error) {
+	switch Fit(strings.ToLower(fit)) {
+	case "", FitContain:
+		return FitContain, nil
+	case FitCover:
+		return FitCover, nil
+	case FitFill:
+		return FitFill, nil
+	case FitExact:
+		return FitExact, nil
+	default:
+		return "", fmt.Errorf("unknown fit mode %q", fit)
+	}
+}
+
+// ParseColor разбирает цвет в формате #rgb, #rrggbb или #rrggbbaa, пустая строка - прозрачный
+func ParseColor(hex string) (color.Color, error) {
+	if hex == "" {
+		return color.Transparent, nil
+	}
+
+	s := strings.TrimPrefix(hex, "#")
+	if len(s) == 3 {
+		s = string([]byte{s[0], s[0], s[1], s[1], s[2], s[2]})
+	}
+	if len(s) == 6 {
+		s += "ff"
+	}
+	if len(s) != 8 {
+		return nil, fmt.Errorf("invalid color %q", hex)
+	}
+
+	v, err := strconv.ParseUint(s, 16, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid color %q: %w", hex, err)
+	}
+
+	return color.NRGBA{R: uint8(v >> 24), G: uint8(v >> 16), B: uint8(v >> 8), A: uint8(v)}, nil
+}
+
+// fitImage приводит изображение к размеру пресета согласно preset.Fit.
+// focal, если задан, определяет центр обрезки для cover
+func fitImage(img image.Image, preset Preset, focal *domain.FocalPoint) image.Image {
+	srcW, srcH := img.Bounds().Dx(), img.Bounds().Dy()
+	if srcW == 0 || srcH == 0 {
+		return img
+	}
+
+	// без одной из сторон точный размер не определен - только вписываем
+	fit := preset.Fit
+	if preset.Width == 0 || preset.Height == 0 {
+		fit = FitContain
+	}
+
+	switch fit {
+	case FitCover:
+		return cover(img, preset, focal)
+	case FitFill:
+		return fill(img, preset)
+	case FitExact:
+		w, h := preset.Width, preset.Height
+		if preset.NoUpscale {
+			w, h = minUint(w, uint(srcW)), minUint(h, uint(srcH))
+		}
+		return resize.Resize(w, h, img, resize.Lanczos3)
+	default:
+		return contain(img, preset)
+	}
+}
+
+func contain(img image.Image, preset Preset) image.Image {
+	srcW, srcH := float64(img.Bounds().Dx()), float64(img.Bounds().Dy())
+
+	scale := math.Inf(1)
+	if preset.Width > 0 {
+		scale = float64(preset.Width) / srcW
+	}
+	if preset.Height > 0 {
+		scale = math.Min(scale, float64(preset.Height)/srcH)
+	}
+	if preset.NoUpscale {
+		scale = math.Min(scale, 1)
+	}
+
+	return scaleImage(img, scale)
+}
+
+// cover выбирает в оригинале окно с пропорциями результата и масштабирует только его
+func cover(img image.Image, preset Preset, focal *domain.FocalPoint) image.Image {
+	bounds := img.Bounds()
+	srcW, srcH := float64(bounds.Dx()), float64(bounds.Dy())
+
+	scale := math.Max(float64(preset.Width)/srcW, float64(preset.Height)/srcH)
+	if preset.NoUpscale {
+		scale = math.Min(scale, 1)
+	}
+
+	outW := minInt(int(preset.Width), int(math.Max(1, math.Round(srcW*scale))))
+	outH := minInt(int(preset.Height), int(math.Max(1, math.Round(srcH*scale))))
+	winW := clampInt(int(math.Round(float64(outW)/scale)), 1, bounds.Dx())
+	winH := clampInt(int(math.Round(float64(outH)/scale)), 1, bounds.Dy())
+
+	var window image.Rectangle
+	switch {
+	case focal != nil:
+		window = focalRect(bounds, winW, winH, focal.X, focal.Y)
+	case preset.Crop == CropSmart:
+		window = smartCropRect(img, winW, winH)
+	default:
+		window = centerRect(bounds, winW, winH)
+	}
+
+	cropped := crop(img, window)
+	if winW == outW && winH == outH {
+		return cropped
+	}
+	return resize.Resize(uint(outW), uint(outH), cropped, resize.Lanczos3)
+}
+
+func fill(img image.Image, preset Preset) image.Image {
+	contained := contain(img, preset)
+
+	canvas := image.NewNRGBA(image.Rect(0, 0, int(preset.Width), int(preset.Height)))
+	draw.Draw(canvas, canvas.Bounds(), image.NewUniform(preset.Background), image.Point{}, draw.Src)
+
+	dst := centerRect(canvas.Bounds(), contained.Bounds().Dx(), contained.Bounds().Dy())
+	draw.Draw(canvas, dst, contained, contained.Bounds().Min, draw.Over)
+
+	return canvas
+}
+
+func scaleImage(img image.Image, scale float64) image.Image {
+	if scale == 1 {
+		return img
+	}
+
+	w := uint(math.Max(1, math.Round(float64(img.Bounds().Dx())*scale)))
+	h := uint(math.Max(1, math.Round(float64(img.Bounds().Dy())*scale)))
+	return resize.Resize(w, h, img, resize.Lanczos3)
+}
+
+// centerRect - прямоугольник w x h в центре bounds
+func centerRect(bounds image.Rectangle, w, h int) image.Rectangle {
+	x := bounds.Min.X + (bounds.Dx()-w)/2
+	y := bounds.Min.Y + (bounds.Dy()-h)/2
+	return image.Rect(x, y, x+w, y+h)
+}
+
+func crop(img image.Image, rect image.Rectangle) image.Image {
+	dst := image.NewNRGBA(image.Rect(0, 0, rect.Dx(), rect.Dy()))
+	draw.Draw(dst, dst.Bounds(), img, rect.Min, draw.Src)
+	return dst
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func minUint(a, b uint) uint {
+	if a < b {
+		return a
+	}
+	return b
+}
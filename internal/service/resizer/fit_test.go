@@ -0,0 +1,65 @@
+package resizer
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestFitImage(t *testing.T) {
+	portrait := image.NewNRGBA(image.Rect(0, 0, 300, 600))
+	landscape := image.NewNRGBA(image.Rect(0, 0, 600, 300))
+	small := image.NewNRGBA(image.Rect(0, 0, 50, 40))
+
+	tests := []struct {
+		name      string
+		src       image.Image
+		fit       Fit
+		width     uint
+		height    uint
+		noUpscale bool
+		wantW     int
+		wantH     int
+	}{
+		{"contain portrait", portrait, FitContain, 200, 100, false, 50, 100},
+		{"contain landscape", landscape, FitContain, 200, 100, false, 200, 100},
+		{"contain small", small, FitContain, 200, 100, false, 125, 100},
+		{"contain small no upscale", small, FitContain, 200, 100, true, 50, 40},
+		{"contain width only", portrait, FitContain, 200, 0, false, 200, 400},
+
+		{"cover portrait", portrait, FitCover, 200, 100, false, 200, 100},
+		{"cover landscape", landscape, FitCover, 200, 100, false, 200, 100},
+		{"cover small", small, FitCover, 200, 100, false, 200, 100},
+		{"cover portrait no upscale", portrait, FitCover, 200, 100, true, 200, 100},
+		{"cover small no upscale", small, FitCover, 200, 100, true, 50, 40},
+
+		{"fill portrait", portrait, FitFill, 200, 100, false, 200, 100},
+		{"fill landscape", landscape, FitFill, 200, 100, false, 200, 100},
+		{"fill small", small, FitFill, 200, 100, false, 200, 100},
+		{"fill small no upscale", small, FitFill, 200, 100, true, 200, 100},
+
+		{"exact portrait", portrait, FitExact, 200, 100, false, 200, 100},
+		{"exact landscape", landscape, FitExact, 200, 100, false, 200, 100},
+		{"exact small", small, FitExact, 200, 100, false, 200, 100},
+		{"exact portrait no upscale", portrait, FitExact, 200, 100, true, 200, 100},
+		{"exact small no upscale", small, FitExact, 200, 100, true, 50, 40},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			preset := Preset{
+				Name:       "test",
+				Width:      tt.width,
+				Height:     tt.height,
+				Fit:        tt.fit,
+				Background: color.Transparent,
+				NoUpscale:  tt.noUpscale,
+			}
+
+			bounds := fitImage(tt.src, preset, nil).Bounds()
+			if bounds.Dx() != tt.wantW || bounds.Dy() != tt.wantH {
+				t.Errorf("got %dx%d, want %dx%d", bounds.Dx(), bounds.Dy(), tt.wantW, tt.wantH)
+			}
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package resizer
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"github.com/menyasosali/mts/internal/domain"
+	"github.com/rwcarlsen/goexif/exif"
+	"image"
+	"strings"
+)
+
+// readMetadata собирает domain.ImageMetadata по декодированному оригиналу и его байтам, без EXIF
+func readMetadata(img image.Image, format string, data []byte) *domain.ImageMetadata {
+	bounds := img.Bounds()
+	return &domain.ImageMetadata{
+		Width:      bounds.Dx(),
+		Height:     bounds.Dy(),
+		Format:     format,
+		Size:       int64(len(data)),
+		ColorModel: colorModelName(img),
+	}
+}
+
+// decodeEXIF читает EXIF оригинала. nil без ошибки - EXIF в файле нет или в нем нет нужных полей
+func decodeEXIF(format string, data []byte) (*domain.EXIF, error) {
+	block := exifBlock(format, data)
+	if block == nil {
+		return nil, nil
+	}
+
+	x, err := exif.Decode(bytes.NewReader(block))
+	if err != nil && (x == nil || exif.IsCriticalError(err)) {
+		return nil, fmt.Errorf("failed to decode exif: %w", err)
+	}
+	// некритичная ошибка - поврежден один из вложенных IFD, остальные поля читаются
+
+	return readEXIF(x), nil
+}
+
+var _exifHeader = []byte("Exif\x00\x00")
+
+// exifBlock возвращает TIFF с EXIF из APP1 (JPEG), чанка EXIF (WebP) или eXIf (PNG).
+// nil - EXIF нет или формат его не поддерживает
+func exifBlock(format string, data []byte) []byte {
+	switch format {
+	case "jpeg":
+		// SOI, затем сегменты 0xFF marker + size (BE, включая сами 2 байта) до SOS
+		if len(data) < 2 || data[0] != 0xFF || data[1] != 0xD8 {
+			return nil
+		}
+		for segments := data[2:]; len(segments) >= 4 && segments[0] == 0xFF; {
+			marker := segments[1]
+			if marker == 0xDA || marker == 0xD9 {
+				return nil
+			}
+			size := int(binary.BigEndian.Uint16(segments[2:4]))
+			if size < 2 || size > len(segments)-2 {
+				return nil
+			}
+			payload := segments[4 : 2+size]
+			if marker == 0xE1 && bytes.HasPrefix(payload, _exifHeader) {
+				return payload[len(_exifHeader):]
+			}
+			segments = segments[2+size:]
+		}
+	case "webp":
+		// "RIFF" size "WEBP", затем чанки fourcc + size (LE) + данные с выравниванием до 2
+		if len(data) < 12 {
+			return nil
+		}
+		for chunks := data[12:]; len(chunks) >= 8; {
+			size := int(binary.LittleEndian.Uint32(chunks[4:8]))
+			if size > len(chunks)-8 {
+				return nil
+			}
+			if string(chunks[:4]) == "EXIF" {
+				// заголовок Exif спецификация не требует, но некоторые кодировщики его пишут
+				return bytes.TrimPrefix(chunks[8:8+size], _exifHeader)
+			}
+			// у последнего чанка нечетного размера байт выравнивания может отсутствовать
+			next := 8 + size + size%2
+			if next > len(chunks) {
+				return nil
+			}
+			chunks = chunks[next:]
+		}
+	case "png":
+		// сигнатура 8 байт, затем чанки size (BE) + тип + данные + crc
+		if len(data) < 8 {
+			return nil
+		}
+		for chunks := data[8:]; len(chunks) >= 12; {
+			size := int(binary.BigEndian.Uint32(chunks[:4]))
+			if size > len(chunks)-12 {
+				return nil
+			}
+			switch string(chunks[4:8]) {
+			case "eXIf":
+				return chunks[8 : 8+size]
+			case "IDAT", "IEND":
+				// eXIf идет до данных изображения
+				return nil
+			}
+			chunks = chunks[12+size:]
+		}
+	}
+	return nil
+}
+
+func readEXIF(x *exif.Exif) *domain.EXIF {
+	result := &domain.EXIF{
+		CameraMake:  exifString(x, exif.Make),
+		CameraModel: exifString(x, exif.Model),
+	}
+
+	if tag, err := x.Get(exif.Orientation); err == nil {
+		if orientation, err := tag.Int(0); err == nil && orientation >= 1 && orientation <= 8 {
+			result.Orientation = orientation
+		}
+	}
+
+	// DateTimeOriginal, если его нет - DateTime
+	if capturedAt, err := x.DateTime(); err == nil {
+		result.CapturedAt = &capturedAt
+	}
+
+	// 0,0 пишут камеры без фиксации GPS
+	if lat, long, err := x.LatLong(); err == nil && (lat != 0 || long != 0) {
+		result.GPS = &domain.GPS{Latitude: lat, Longitude: long}
+	}
+
+	if *result == (domain.EXIF{}) {
+		return nil
+	}
+	return result
+}
+
+func exifString(x *exif.Exif, name exif.FieldName) string {
+	tag, err := x.Get(name)
+	if err != nil {
+		return ""
+	}
+	value, err := tag.StringVal()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(strings.TrimRight(value, "\x00"))
+}
+
+func colorModelName(img image.Image) string {
+	switch img.(type) {
+	case *image.YCbCr:
+		return "ycbcr"
+	case *image.NYCbCrA:
+		return "nycbcra"
+	case *image.RGBA:
+		return "rgba"
+	case *image.RGBA64:
+		return "rgba64"
+	case *image.NRGBA:
+		return "nrgba"
+	case *image.NRGBA64:
+		return "nrgba64"
+	case *image.Gray:
+		return "gray"
+	case *image.Gray16:
+		return "gray16"
+	case *image.CMYK:
+		return "cmyk"
+	case *image.Paletted:
+		return "paletted"
+	case *image.Alpha, *image.Alpha16:
+		return "alpha"
+	default:
+		return fmt.Sprintf("%T", img)
+	}
+}
@@ -0,0 +1,53 @@
+package resizer
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+// webpFile собирает RIFF-контейнер WebP из готовых чанков
+func webpFile(chunks ...[]byte) []byte {
+	body := append([]byte("WEBP"), bytes.Join(chunks, nil)...)
+	header := append([]byte("RIFF"), binary.LittleEndian.AppendUint32(nil, uint32(len(body)))...)
+	return append(header, body...)
+}
+
+// webpChunk - чанк fourcc + size + data, pad добавляет байт выравнивания для нечетного размера
+func webpChunk(fourcc string, data []byte, pad bool) []byte {
+	chunk := append([]byte(fourcc), binary.LittleEndian.AppendUint32(nil, uint32(len(data)))...)
+	chunk = append(chunk, data...)
+	if pad && len(data)%2 == 1 {
+		chunk = append(chunk, 0)
+	}
+	return chunk
+}
+
+func TestExifBlockWebP(t *testing.T) {
+	tiff := []byte("II*\x00\x08\x00\x00\x00")
+
+	tests := []struct {
+		name string
+		data []byte
+		want []byte
+	}{
+		{"odd last chunk without pad", webpFile(webpChunk("VP8X", []byte{1, 2, 3}, false)), nil},
+		{"odd chunk with pad before exif", webpFile(
+			webpChunk("VP8X", []byte{1, 2, 3}, true),
+			webpChunk("EXIF", tiff, true),
+		), tiff},
+		{"exif with header", webpFile(webpChunk("EXIF", append([]byte("Exif\x00\x00"), tiff...), true)), tiff},
+		{"truncated exif chunk", webpFile(webpChunk("EXIF", tiff, true))[:20], nil},
+		{"no chunks", webpFile(), nil},
+		{"short header", []byte("RIFF"), nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := exifBlock("webp", tt.data)
+			if !bytes.Equal(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
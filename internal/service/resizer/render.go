@@ -0,0 +1,33 @@
+package resizer
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/menyasosali/mts/internal/domain"
+	"image"
+)
+
+// Render масштабирует и кодирует изображение по произвольному пресету (ресайз на лету в gateway).
+// Возвращает итоговый формат - для FormatOriginal это формат исходника
+func Render(original []byte, preset Preset, format Format, focal *domain.FocalPoint) ([]byte, Format, error) {
+	originalImage, sourceFormat, err := image.Decode(bytes.NewReader(original))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to decode original image: %w", err)
+	}
+
+	format, err = format.resolve(sourceFormat)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if preset.Background == nil {
+		preset.Background = image.White
+	}
+
+	data, err := encode(fitImage(originalImage, preset, focal), format, preset.Encode)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return data, format, nil
+}
@@ -4,105 +4,262 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"github.com/menyasosali/mts/config"
 	"github.com/menyasosali/mts/internal/domain"
 	"github.com/menyasosali/mts/internal/service/filestorer"
 	"github.com/menyasosali/mts/internal/service/kafka"
 	"github.com/menyasosali/mts/pkg/logger"
-	"github.com/nfnt/resize"
 	"image"
-	"image/gif"
-	"image/jpeg"
-	"image/png"
-	"path/filepath"
+	"image/color"
 )
 
 // использует клиент кафки(consumer), minio
 
 // использ в cmd/worker/main.go
 
+// Preset - размер превью, Width или Height может быть 0 - тогда считается по пропорциям
+type Preset struct {
+	Name       string
+	Width      uint
+	Height     uint
+	Fit        Fit
+	Crop       Crop
+	Background color.Color
+	NoUpscale  bool
+	Formats    []Format
+	Encode     EncodeOptions
+}
+
+// FocalPointStore отдает заданный пользователем центр обрезки (db.Store)
+type FocalPointStore interface {
+	GetFocalPoint(context.Context, string) (*domain.FocalPoint, error)
+}
+
+// VariantNotifier сообщает наблюдателям WatchImage о превью, как только оно загружено (db.Store)
+type VariantNotifier interface {
+	NotifyVariant(context.Context, string, domain.Variant) error
+}
+
 type Resizer struct {
-	Logger     logger.Interface
-	FileStorer filestorer.FileStorerInterface
+	Logger      logger.Interface
+	FileStorer  filestorer.FileStorerInterface
+	FocalPoints FocalPointStore
+	Events      VariantNotifier
+	Presets     []Preset
 }
 
-func NewResizer(logger logger.Interface, fileStorer filestorer.FileStorerInterface) *Resizer {
+func NewResizer(logger logger.Interface, fileStorer filestorer.FileStorerInterface, focalPoints FocalPointStore,
+	events VariantNotifier, presets []config.PresetConfig) (*Resizer, error) {
+	parsed, err := parsePresets(presets)
+	if err != nil {
+		return nil, err
+	}
 
 	return &Resizer{
-		Logger:     logger,
-		FileStorer: fileStorer,
+		Logger:      logger,
+		FileStorer:  fileStorer,
+		FocalPoints: focalPoints,
+		Events:      events,
+		Presets:     parsed,
+	}, nil
+}
+
+func parsePresets(presets []config.PresetConfig) ([]Preset, error) {
+	if len(presets) == 0 {
+		return nil, fmt.Errorf("no resize presets configured")
 	}
+
+	parsed := make([]Preset, 0, len(presets))
+	names := make(map[string]struct{}, len(presets))
+	for _, p := range presets {
+		if p.Name == "" {
+			return nil, fmt.Errorf("resize preset name is required")
+		}
+		if _, ok := names[p.Name]; ok {
+			return nil, fmt.Errorf("duplicate resize preset %q", p.Name)
+		}
+		if p.Width == 0 && p.Height == 0 {
+			return nil, fmt.Errorf("resize preset %q: width or height is required", p.Name)
+		}
+		names[p.Name] = struct{}{}
+
+		fit, err := ParseFit(p.Fit)
+		if err != nil {
+			return nil, fmt.Errorf("resize preset %q: %w", p.Name, err)
+		}
+		if fit != FitContain && (p.Width == 0 || p.Height == 0) {
+			return nil, fmt.Errorf("resize preset %q: fit %s requires both width and height", p.Name, fit)
+		}
+
+		crop, err := ParseCrop(p.Crop)
+		if err != nil {
+			return nil, fmt.Errorf("resize preset %q: %w", p.Name, err)
+		}
+
+		background, err := ParseColor(p.Background)
+		if err != nil {
+			return nil, fmt.Errorf("resize preset %q: %w", p.Name, err)
+		}
+
+		formats, err := parseFormats(p.Formats)
+		if err != nil {
+			return nil, fmt.Errorf("resize preset %q: %w", p.Name, err)
+		}
+
+		if p.Quality < 0 || p.Quality > 100 {
+			return nil, fmt.Errorf("resize preset %q: quality must be within [0, 100]", p.Name)
+		}
+
+		pngCompression, err := ParsePNGCompression(p.PNGCompression)
+		if err != nil {
+			return nil, fmt.Errorf("resize preset %q: %w", p.Name, err)
+		}
+
+		parsed = append(parsed, Preset{
+			Name:       p.Name,
+			Width:      p.Width,
+			Height:     p.Height,
+			Fit:        fit,
+			Crop:       crop,
+			Background: background,
+			NoUpscale:  p.NoUpscale,
+			Formats:    formats,
+			Encode: EncodeOptions{
+				Quality:        p.Quality,
+				PNGCompression: pngCompression,
+			},
+		})
+	}
+
+	return parsed, nil
 }
 
-func (r *Resizer) ProcessImage(ctx context.Context, imgKafka kafka.ImgKafka) domain.ImgDescriptor {
-	originalImageBytes, err := r.FileStorer.DownloadImage(ctx, imgKafka.ID)
-	if err != nil {
-		r.Logger.Error(err)
-		return domain.ImgDescriptor{}
+// parseFormats - без явного списка превью сохраняется в формате оригинала
+func parseFormats(formats []string) ([]Format, error) {
+	if len(formats) == 0 {
+		return []Format{FormatOriginal}, nil
 	}
 
-	originalImage, _, err := image.Decode(bytes.NewReader(originalImageBytes))
-	if err != nil {
-		r.Logger.Error(fmt.Sprintf("Failed to decode original image: %v", err))
-		return domain.ImgDescriptor{}
+	parsed := make([]Format, 0, len(formats))
+	for _, f := range formats {
+		format, err := ParseFormat(f)
+		if err != nil {
+			return nil, err
+		}
+		parsed = append(parsed, format)
 	}
+	return parsed, nil
+}
 
-	imageType := filepath.Ext(imgKafka.OriginalURL)
+// selectPresets возвращает запрошенные задачей пресеты, пустой список - все пресеты
+func (r *Resizer) selectPresets(names []string) ([]Preset, error) {
+	if len(names) == 0 {
+		return r.Presets, nil
+	}
 
-	resizedImage512, err := resizeTo(512, originalImage, imageType)
-	if err != nil {
-		r.Logger.Error(fmt.Sprintf("Failed to resize image: %v", err))
+	presets := make([]Preset, 0, len(names))
+	for _, name := range names {
+		found := false
+		for _, preset := range r.Presets {
+			if preset.Name == name {
+				presets = append(presets, preset)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown resize preset %q", name)
+		}
 	}
+	return presets, nil
+}
 
-	resizedImage256, err := resizeTo(256, originalImage, imageType)
+func (r *Resizer) ProcessImage(ctx context.Context, job kafka.ImageJob) (domain.ImgDescriptor, error) {
+	presets, err := r.selectPresets(job.Presets)
 	if err != nil {
-		r.Logger.Error(fmt.Sprintf("Failed to resize image: %v", err))
+		return domain.ImgDescriptor{}, err
 	}
 
-	resizedImage16, err := resizeTo(16, originalImage, imageType)
+	originalImageBytes, err := r.FileStorer.DownloadImage(ctx, job.ObjectKey)
 	if err != nil {
-		r.Logger.Error(fmt.Sprintf("Failed to resize image: %v", err))
+		r.Logger.Error(err)
+		return domain.ImgDescriptor{}, err
 	}
 
-	imgDescriptor := domain.ImgDescriptor{
-		ID:   imgKafka.ID,
-		Name: imgKafka.Name,
-		URL:  imgKafka.OriginalURL,
+	// формат определяем по содержимому, а не по расширению в URL
+	originalImage, sourceFormat, err := image.Decode(bytes.NewReader(originalImageBytes))
+	if err != nil {
+		r.Logger.Error(fmt.Sprintf("Failed to decode original image: %v", err))
+		return domain.ImgDescriptor{}, fmt.Errorf("failed to decode original image: %w", err)
 	}
 
-	imgDescriptor.URL512, err = r.FileStorer.UploadImage(ctx, resizedImage512, imgKafka.Name+"-512")
+	focal, err := r.FocalPoints.GetFocalPoint(ctx, job.ImageID)
 	if err != nil {
 		r.Logger.Error(err)
+		return domain.ImgDescriptor{}, err
 	}
 
-	imgDescriptor.URL256, err = r.FileStorer.UploadImage(ctx, resizedImage256, imgKafka.Name+"-256")
-	if err != nil {
-		r.Logger.Error(err)
+	imgDescriptor := domain.ImgDescriptor{
+		ID:       job.ImageID,
+		Name:     job.Name,
+		URL:      job.OriginalURL,
+		Metadata: readMetadata(originalImage, sourceFormat, originalImageBytes),
 	}
 
-	imgDescriptor.URL16, err = r.FileStorer.UploadImage(ctx, resizedImage16, imgKafka.Name+"-16")
+	// без EXIF превью все равно нарезаются
+	imgDescriptor.Metadata.EXIF, err = decodeEXIF(sourceFormat, originalImageBytes)
 	if err != nil {
-		r.Logger.Error(err)
+		r.Logger.Warn(fmt.Sprintf("Failed to read EXIF of image %s: %v", job.ImageID, err))
 	}
 
-	return imgDescriptor
-}
+	for _, preset := range presets {
+		resizedImage := fitImage(originalImage, preset, focal)
+		bounds := resizedImage.Bounds()
 
-func resizeTo(width uint, originalImage image.Image, imageType string) ([]byte, error) {
-	resizedImage := resize.Resize(width, 0, originalImage, resize.Lanczos3)
-	switch imageType {
-	case "jpg", "jpeg":
-		var jpegBuffer bytes.Buffer
-		err := jpeg.Encode(&jpegBuffer, resizedImage, nil)
-		return jpegBuffer.Bytes(), err
-	case "png":
-		var pngBuffer bytes.Buffer
-		err := png.Encode(&pngBuffer, resizedImage)
-		return pngBuffer.Bytes(), err
-	case "gif":
-		var gifBuffer bytes.Buffer
-		err := gif.Encode(&gifBuffer, resizedImage, nil)
-		return gifBuffer.Bytes(), err
-	default:
-		return nil, fmt.Errorf("unsupported type")
+		encoded := make(map[Format]struct{}, len(preset.Formats))
+		for _, format := range preset.Formats {
+			format, err = format.resolve(sourceFormat)
+			if err != nil {
+				return domain.ImgDescriptor{}, fmt.Errorf("preset %s: %w", preset.Name, err)
+			}
+			if _, ok := encoded[format]; ok {
+				continue
+			}
+			encoded[format] = struct{}{}
+
+			data, err := encode(resizedImage, format, preset.Encode)
+			if err != nil {
+				r.Logger.Error(fmt.Sprintf("Failed to encode image: %v", err))
+				return domain.ImgDescriptor{}, fmt.Errorf("failed to encode preset %s: %w", preset.Name, err)
+			}
+
+			// ключ по id изображения: файлы с одинаковым именем не перезаписывают превью друг друга
+			key := "variants/" + job.ImageID + "/" + preset.Name + format.Extension()
+			url, err := r.FileStorer.UploadImage(ctx, bytes.NewReader(data), int64(len(data)), key, format.ContentType())
+			if err != nil {
+				r.Logger.Error(err)
+				return domain.ImgDescriptor{}, err
+			}
+
+			variant := domain.Variant{
+				Preset:      preset.Name,
+				Format:      string(format),
+				ContentType: format.ContentType(),
+				Key:         key,
+				URL:         url,
+				Width:       bounds.Dx(),
+				Height:      bounds.Dy(),
+			}
+			imgDescriptor.Variants = append(imgDescriptor.Variants, variant)
+
+			// уведомление только ускоряет WatchImage, превью и так придет в событии ready
+			err = r.Events.NotifyVariant(ctx, job.ImageID, variant)
+			if err != nil {
+				r.Logger.Warn(fmt.Sprintf("Failed to notify variant %s/%s of image %s: %v",
+					preset.Name, format, job.ImageID, err))
+			}
+		}
 	}
+
+	return imgDescriptor, nil
 }
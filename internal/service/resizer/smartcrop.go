@@ -0,0 +1,182 @@
+package resizer
+
+import (
+	"fmt"
+	"github.com/nfnt/resize"
+	"image"
+	"image/color"
+	"math"
+)
+
+type Crop string
+
+const (
+	// CropCenter обрезает по центру
+	CropCenter Crop = "center"
+	// CropSmart выбирает окно с максимумом границ и энтропии
+	CropSmart Crop = "smart"
+)
+
+const (
+	_analysisSize    = 128
+	_entropyBlock    = 8
+	_entropyBins     = 16
+	_centerBiasShare = 0.05
+)
+
+func ParseCrop(crop string) (Crop, error) {
+	switch Crop(crop) {
+	case "", CropCenter:
+		return CropCenter, nil
+	case CropSmart:
+		return CropSmart, nil
+	default:
+		return "", fmt.Errorf("unknown crop strategy %q", crop)
+	}
+}
+
+// smartCropRect ищет окно w x h (в координатах img) с наибольшей суммой карты важности.
+// Карта считается на уменьшенной копии: модуль градиента яркости плюс локальная энтропия
+func smartCropRect(img image.Image, w, h int) image.Rectangle {
+	bounds := img.Bounds()
+	if w >= bounds.Dx() && h >= bounds.Dy() {
+		return bounds
+	}
+
+	scale := math.Min(1, float64(_analysisSize)/math.Max(float64(bounds.Dx()), float64(bounds.Dy())))
+	small := img
+	if scale < 1 {
+		small = resize.Resize(uint(math.Max(1, float64(bounds.Dx())*scale)), 0, img, resize.Bilinear)
+	}
+	sw, sh := small.Bounds().Dx(), small.Bounds().Dy()
+	scaleX, scaleY := float64(sw)/float64(bounds.Dx()), float64(sh)/float64(bounds.Dy())
+
+	winW := clampInt(int(math.Round(float64(w)*scaleX)), 1, sw)
+	winH := clampInt(int(math.Round(float64(h)*scaleY)), 1, sh)
+
+	integral := integralImage(saliency(small), sw, sh)
+	windowSum := func(x, y int) float64 {
+		return integral[(y+winH)*(sw+1)+x+winW] - integral[y*(sw+1)+x+winW] -
+			integral[(y+winH)*(sw+1)+x] + integral[y*(sw+1)+x]
+	}
+
+	total := integral[len(integral)-1]
+	if total == 0 {
+		return centerRect(bounds, w, h)
+	}
+
+	// небольшой штраф за удаление от центра, чтобы при равных оценках оставался центр
+	centerX, centerY := float64(sw-winW)/2, float64(sh-winH)/2
+	bias := _centerBiasShare * total / math.Max(1, math.Hypot(centerX, centerY))
+
+	bestX, bestY, bestScore := 0, 0, math.Inf(-1)
+	for y := 0; y <= sh-winH; y++ {
+		for x := 0; x <= sw-winW; x++ {
+			score := windowSum(x, y) - bias*math.Hypot(float64(x)-centerX, float64(y)-centerY)
+			if score > bestScore {
+				bestX, bestY, bestScore = x, y, score
+			}
+		}
+	}
+
+	x := bounds.Min.X + clampInt(int(math.Round(float64(bestX)/scaleX)), 0, bounds.Dx()-w)
+	y := bounds.Min.Y + clampInt(int(math.Round(float64(bestY)/scaleY)), 0, bounds.Dy()-h)
+	return image.Rect(x, y, x+w, y+h)
+}
+
+// focalRect - окно w x h с центром в точке (fx, fy), заданной в долях от размера
+func focalRect(bounds image.Rectangle, w, h int, fx, fy float64) image.Rectangle {
+	cx := bounds.Min.X + int(math.Round(fx*float64(bounds.Dx())))
+	cy := bounds.Min.Y + int(math.Round(fy*float64(bounds.Dy())))
+
+	x := clampInt(cx-w/2, bounds.Min.X, bounds.Max.X-w)
+	y := clampInt(cy-h/2, bounds.Min.Y, bounds.Max.Y-h)
+	return image.Rect(x, y, x+w, y+h)
+}
+
+func saliency(img image.Image) []float64 {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+
+	luma := make([]float64, w*h)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			luma[y*w+x] = float64(color.GrayModel.Convert(img.At(bounds.Min.X+x, bounds.Min.Y+y)).(color.Gray).Y)
+		}
+	}
+
+	edges := make([]float64, w*h)
+	maxEdge := 0.0
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			gx := luma[y*w+clampInt(x+1, 0, w-1)] - luma[y*w+clampInt(x-1, 0, w-1)]
+			gy := luma[clampInt(y+1, 0, h-1)*w+x] - luma[clampInt(y-1, 0, h-1)*w+x]
+			edges[y*w+x] = math.Abs(gx) + math.Abs(gy)
+			maxEdge = math.Max(maxEdge, edges[y*w+x])
+		}
+	}
+
+	entropy := make([]float64, w*h)
+	maxEntropy := 0.0
+	for by := 0; by < h; by += _entropyBlock {
+		for bx := 0; bx < w; bx += _entropyBlock {
+			var hist [_entropyBins]int
+			n := 0
+			for y := by; y < by+_entropyBlock && y < h; y++ {
+				for x := bx; x < bx+_entropyBlock && x < w; x++ {
+					hist[int(luma[y*w+x])*_entropyBins/256]++
+					n++
+				}
+			}
+
+			e := 0.0
+			for _, count := range hist {
+				if count > 0 {
+					p := float64(count) / float64(n)
+					e -= p * math.Log2(p)
+				}
+			}
+			maxEntropy = math.Max(maxEntropy, e)
+
+			for y := by; y < by+_entropyBlock && y < h; y++ {
+				for x := bx; x < bx+_entropyBlock && x < w; x++ {
+					entropy[y*w+x] = e
+				}
+			}
+		}
+	}
+
+	scores := make([]float64, w*h)
+	for i := range scores {
+		if maxEdge > 0 {
+			scores[i] += edges[i] / maxEdge
+		}
+		if maxEntropy > 0 {
+			scores[i] += entropy[i] / maxEntropy
+		}
+	}
+
+	return scores
+}
+
+func integralImage(values []float64, w, h int) []float64 {
+	integral := make([]float64, (w+1)*(h+1))
+	for y := 0; y < h; y++ {
+		rowSum := 0.0
+		for x := 0; x < w; x++ {
+			rowSum += values[y*w+x]
+			integral[(y+1)*(w+1)+x+1] = integral[y*(w+1)+x+1] + rowSum
+		}
+	}
+	return integral
+}
+
+func clampInt(v, lo, hi int) int {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
@@ -0,0 +1,70 @@
+package resizer
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestFocalRect(t *testing.T) {
+	bounds := image.Rect(0, 0, 400, 200)
+	offset := image.Rect(10, 20, 410, 220)
+
+	tests := []struct {
+		name   string
+		bounds image.Rectangle
+		fx, fy float64
+		want   image.Rectangle
+	}{
+		{"center", bounds, 0.5, 0.5, image.Rect(150, 50, 250, 150)},
+		{"top left corner", bounds, 0, 0, image.Rect(0, 0, 100, 100)},
+		{"bottom right corner", bounds, 1, 1, image.Rect(300, 100, 400, 200)},
+		{"left quarter", bounds, 0.25, 0.5, image.Rect(50, 50, 150, 150)},
+		{"clamped to right edge", bounds, 0.9, 0.5, image.Rect(300, 50, 400, 150)},
+		{"offset bounds", offset, 0.5, 0.5, image.Rect(160, 70, 260, 170)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := focalRect(tt.bounds, 100, 100, tt.fx, tt.fy)
+			if got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSmartCropRect(t *testing.T) {
+	flat := image.NewNRGBA(image.Rect(0, 0, 300, 100))
+
+	// мелкая шахматная клетка справа - единственная область с деталями
+	detailed := image.NewNRGBA(image.Rect(0, 0, 300, 100))
+	for y := 20; y < 80; y++ {
+		for x := 220; x < 280; x++ {
+			if (x/4+y/4)%2 == 0 {
+				detailed.Set(x, y, color.White)
+			}
+		}
+	}
+
+	// contains - область, которую окно обязано захватить целиком
+	tests := []struct {
+		name     string
+		img      image.Image
+		w, h     int
+		contains image.Rectangle
+	}{
+		{"window covers image", flat, 300, 100, image.Rect(0, 0, 300, 100)},
+		{"flat image centered", flat, 100, 100, image.Rect(100, 0, 200, 100)},
+		{"window follows details", detailed, 100, 100, image.Rect(220, 20, 280, 80)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := smartCropRect(tt.img, tt.w, tt.h)
+			if got.Dx() != tt.w || got.Dy() != tt.h || !tt.contains.In(got) {
+				t.Errorf("got %v, want %dx%d window containing %v", got, tt.w, tt.h, tt.contains)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package webhook
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+var errForbiddenAddress = errors.New("webhook address is not public")
+
+// addressGuard не дает webhook обращаться во внутреннюю сеть: callback_url задает клиент, и без
+// проверки gateway можно заставить ходить на localhost, metadata облака или соседние сервисы.
+// Проверяется уже разрешенный IP при каждом соединении, поэтому не помогают ни DNS, указывающий
+// на внутренний адрес, ни редирект. allowed - сети из Cfg.AllowedNetworks, куда ходить можно
+type addressGuard struct {
+	allowed []*net.IPNet
+}
+
+func newAddressGuard(networks []string) (*addressGuard, error) {
+	guard := &addressGuard{}
+	for _, network := range networks {
+		_, ipNet, err := net.ParseCIDR(network)
+		if err != nil {
+			return nil, fmt.Errorf("invalid allowed network %q: %w", network, err)
+		}
+		guard.allowed = append(guard.allowed, ipNet)
+	}
+
+	return guard, nil
+}
+
+// control вызывается net.Dialer перед соединением с уже разрешенным адресом
+func (g *addressGuard) control(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return fmt.Errorf("%s: %w", host, errForbiddenAddress)
+	}
+
+	for _, network := range g.allowed {
+		if network.Contains(ip) {
+			return nil
+		}
+	}
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() {
+		return fmt.Errorf("%s: %w", ip, errForbiddenAddress)
+	}
+
+	return nil
+}
+
+// newClient - http.Client для webhooks, соединения которого проходят через addressGuard. Прокси из
+// окружения не используется: иначе проверялся бы адрес прокси, а не получателя
+func newClient(timeout time.Duration, guard *addressGuard) *http.Client {
+	dialer := &net.Dialer{Timeout: timeout, Control: guard.control}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{Timeout: timeout, Transport: transport}
+}
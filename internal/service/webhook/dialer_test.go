@@ -0,0 +1,78 @@
+package webhook
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestAddressGuard(t *testing.T) {
+	guard, err := newAddressGuard([]string{"10.20.0.0/16"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		address string
+		allowed bool
+	}{
+		{"93.184.216.34:443", true},
+		{"[2606:2800:220:1:248:1893:25c8:1946]:443", true},
+		{"10.20.3.4:80", true},
+		{"127.0.0.1:80", false},
+		{"[::1]:80", false},
+		{"10.0.0.1:80", false},
+		{"172.16.5.5:80", false},
+		{"192.168.1.1:80", false},
+		{"169.254.169.254:80", false},
+		{"[fe80::1]:80", false},
+		{"[fd00::1]:80", false},
+		{"[::ffff:127.0.0.1]:80", false},
+		{"0.0.0.0:80", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.address, func(t *testing.T) {
+			err := guard.control("tcp", tt.address, nil)
+			switch {
+			case tt.allowed && err != nil:
+				t.Errorf("unexpected error: %v", err)
+			case !tt.allowed && !errors.Is(err, errForbiddenAddress):
+				t.Errorf("got %v, want errForbiddenAddress", err)
+			}
+		})
+	}
+}
+
+func TestClientRejectsLoopback(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {}))
+	defer server.Close()
+
+	guard, err := newAddressGuard(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = newClient(time.Second, guard).Get(server.URL)
+	if !errors.Is(err, errForbiddenAddress) {
+		t.Fatalf("got %v, want errForbiddenAddress", err)
+	}
+
+	guard, err = newAddressGuard([]string{"127.0.0.0/8"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := newClient(time.Second, guard).Get(server.URL)
+	if err != nil {
+		t.Fatalf("allowed network: %v", err)
+	}
+	resp.Body.Close()
+}
+
+func TestNewAddressGuardRejectsBadNetwork(t *testing.T) {
+	_, err := newAddressGuard([]string{"10.0.0.0"})
+	if err == nil {
+		t.Fatal("expected error for network without prefix length")
+	}
+}
@@ -0,0 +1,219 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/menyasosali/mts/config"
+	"github.com/menyasosali/mts/internal/domain"
+	"github.com/menyasosali/mts/internal/service/db"
+	"github.com/menyasosali/mts/pkg/logger"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// _maxErrorBody - сколько байт ответа получателя сохраняется в журнал при ошибке
+const _maxErrorBody = 512
+
+// Store - доставки и изображения в базе (db.Store). Доставки создает триггер из 013_webhooks,
+// когда изображение переходит в ready или failed
+type Store interface {
+	ClaimWebhookDeliveries(ctx context.Context, limit int, lease time.Duration) ([]domain.WebhookDelivery, error)
+	RecordWebhookAttempt(ctx context.Context, delivery domain.WebhookDelivery, attempt domain.WebhookAttempt) error
+	GetImageByID(ctx context.Context, imageID string) (*domain.ImgDescriptor, error)
+}
+
+// Dispatcher отправляет webhooks по расписанию из базы. Ответ 2xx - доставлено, иначе попытка
+// повторяется через Cfg.RetrySchedule
+type Dispatcher struct {
+	Logger logger.Interface
+	Store  Store
+	Client *http.Client
+	Cfg    config.WebhookConfig
+}
+
+func NewDispatcher(logger logger.Interface, store Store, cfg config.WebhookConfig) (*Dispatcher, error) {
+	err := validateConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("invalid webhook config: %w", err)
+	}
+
+	guard, err := newAddressGuard(cfg.AllowedNetworks)
+	if err != nil {
+		return nil, fmt.Errorf("invalid webhook config: %w", err)
+	}
+
+	return &Dispatcher{
+		Logger: logger,
+		Store:  store,
+		Client: newClient(cfg.Timeout, guard),
+		Cfg:    cfg,
+	}, nil
+}
+
+// validateConfig проверяет значения, с которыми dispatcher не может работать: нулевой PollInterval
+// роняет ticker, нулевой Timeout отключает таймаут запроса. Пустой RetrySchedule допустим - без повторов
+func validateConfig(cfg config.WebhookConfig) error {
+	switch {
+	case cfg.Timeout <= 0:
+		return fmt.Errorf("timeout must be positive, got %s", cfg.Timeout)
+	case cfg.PollInterval <= 0:
+		return fmt.Errorf("poll interval must be positive, got %s", cfg.PollInterval)
+	case cfg.BatchSize <= 0:
+		return fmt.Errorf("batch size must be positive, got %d", cfg.BatchSize)
+	}
+	for i, delay := range cfg.RetrySchedule {
+		if delay <= 0 {
+			return fmt.Errorf("retry schedule delay %d must be positive, got %s", i+1, delay)
+		}
+	}
+
+	return nil
+}
+
+func (d *Dispatcher) Start(ctx context.Context) {
+	d.Logger.Info("Webhook dispatcher started")
+	go d.run(ctx)
+}
+
+func (d *Dispatcher) run(ctx context.Context) {
+	ticker := time.NewTicker(d.Cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			d.Logger.Info("Webhook dispatcher stopped")
+			return
+		case <-ticker.C:
+			err := d.dispatchBatch(ctx)
+			if err != nil {
+				d.Logger.Error(fmt.Sprintf("Webhook dispatcher error: %v", err))
+			}
+		}
+	}
+}
+
+// dispatchBatch параллельно делает по попытке для доставок, которым пора
+func (d *Dispatcher) dispatchBatch(ctx context.Context) error {
+	// lease с запасом на таймаут запроса и запись результата
+	deliveries, err := d.Store.ClaimWebhookDeliveries(ctx, d.Cfg.BatchSize, 2*d.Cfg.Timeout+time.Minute)
+	if err != nil {
+		return err
+	}
+
+	var wg sync.WaitGroup
+	for _, delivery := range deliveries {
+		wg.Add(1)
+		go func(delivery domain.WebhookDelivery) {
+			defer wg.Done()
+			d.deliver(ctx, delivery)
+		}(delivery)
+	}
+	wg.Wait()
+
+	return nil
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, delivery domain.WebhookDelivery) {
+	attempt := domain.WebhookAttempt{Attempt: delivery.Attempts + 1}
+
+	if delivery.Payload == nil {
+		img, err := d.Store.GetImageByID(ctx, delivery.ImageID)
+		if err != nil {
+			if !errors.Is(err, db.ErrImageNotFound) {
+				d.Logger.Error(fmt.Sprintf("Failed to get image %s for webhook %d: %v", delivery.ImageID,
+					delivery.ID, err))
+				return
+			}
+			// изображение удалили раньше, чем ушел webhook
+			attempt.Error = "image is deleted"
+			d.finish(ctx, delivery, attempt, domain.WebhookFailed)
+			return
+		}
+
+		delivery.Payload, err = newPayload(delivery, img)
+		if err != nil {
+			d.Logger.Error(fmt.Sprintf("Failed to build webhook %d payload: %v", delivery.ID, err))
+			return
+		}
+	}
+
+	start := time.Now()
+	statusCode, err := d.post(ctx, delivery)
+	attempt.StatusCode = statusCode
+	attempt.Duration = time.Since(start)
+	if err != nil {
+		attempt.Error = err.Error()
+	}
+
+	switch {
+	case err == nil:
+		d.finish(ctx, delivery, attempt, domain.WebhookDelivered)
+	case attempt.Attempt > len(d.Cfg.RetrySchedule):
+		d.Logger.Warn(fmt.Sprintf("Webhook %d to %s failed after %d attempts: %v", delivery.ID, delivery.URL,
+			attempt.Attempt, err))
+		d.finish(ctx, delivery, attempt, domain.WebhookFailed)
+	default:
+		delay := d.Cfg.RetrySchedule[attempt.Attempt-1]
+		d.Logger.Warn(fmt.Sprintf("Webhook %d to %s failed (attempt %d), retry in %s: %v", delivery.ID,
+			delivery.URL, attempt.Attempt, delay, err))
+		delivery.NextAttemptAt = time.Now().Add(delay)
+		d.finish(ctx, delivery, attempt, domain.WebhookPending)
+	}
+}
+
+func (d *Dispatcher) post(ctx context.Context, delivery domain.WebhookDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, delivery.Event)
+	req.Header.Set(HeaderDelivery, strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(d.Cfg.SigningSecret, timestamp, delivery.Payload))
+
+	resp, err := d.Client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, _maxErrorBody))
+		body = bytes.TrimSpace(body)
+		if len(body) == 0 {
+			return resp.StatusCode, fmt.Errorf("unexpected status %s", resp.Status)
+		}
+		return resp.StatusCode, fmt.Errorf("unexpected status %s: %s", resp.Status, body)
+	}
+
+	// дочитываем тело, чтобы соединение вернулось в пул
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, _maxErrorBody))
+	return resp.StatusCode, nil
+}
+
+func (d *Dispatcher) finish(ctx context.Context, delivery domain.WebhookDelivery, attempt domain.WebhookAttempt,
+	status domain.WebhookStatus) {
+	delivery.Status = status
+	delivery.Attempts = attempt.Attempt
+	delivery.LastStatusCode = attempt.StatusCode
+	delivery.LastError = attempt.Error
+	if status == domain.WebhookDelivered {
+		now := time.Now()
+		delivery.DeliveredAt = &now
+	}
+
+	err := d.Store.RecordWebhookAttempt(ctx, delivery, attempt)
+	if err != nil {
+		// после lease доставка вернется в работу и попытка повторится
+		d.Logger.Error(fmt.Sprintf("Failed to record webhook %d attempt: %v", delivery.ID, err))
+	}
+}
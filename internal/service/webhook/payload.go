@@ -0,0 +1,99 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"github.com/menyasosali/mts/internal/domain"
+	"strconv"
+	"time"
+)
+
+// Заголовки запроса webhook. Подпись - hex(HMAC-SHA256(secret, timestamp + "." + body)) с
+// префиксом "sha256=". Получатель проверяет подпись и отбрасывает запросы со старым timestamp
+const (
+	HeaderSignature = "X-Webhook-Signature"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderDelivery  = "X-Webhook-Delivery"
+)
+
+// Sign считает значение заголовка X-Webhook-Signature
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Payload - тело webhook
+type Payload struct {
+	DeliveryID int64      `json:"deliveryId"`
+	Event      string     `json:"event"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	Image      Descriptor `json:"image"`
+}
+
+// Descriptor - изображение в том виде, в каком его отдает GetImageByID
+type Descriptor struct {
+	ImageID       string                `json:"imageID"`
+	Name          string                `json:"name"`
+	OriginalURL   string                `json:"originalUrl"`
+	Status        domain.ImageStatus    `json:"status"`
+	FailureReason string                `json:"failureReason,omitempty"`
+	Variants      []Variant             `json:"variants"`
+	FocalPoint    *FocalPoint           `json:"focalPoint,omitempty"`
+	Metadata      *domain.ImageMetadata `json:"metadata,omitempty"`
+	CreatedAt     time.Time             `json:"createdAt"`
+	UpdatedAt     time.Time             `json:"updatedAt"`
+}
+
+type Variant struct {
+	Name        string `json:"name"`
+	Format      string `json:"format"`
+	ContentType string `json:"contentType"`
+	URL         string `json:"url"`
+	Width       int    `json:"width"`
+	Height      int    `json:"height"`
+}
+
+type FocalPoint struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+func newPayload(delivery domain.WebhookDelivery, img *domain.ImgDescriptor) ([]byte, error) {
+	descriptor := Descriptor{
+		ImageID:       img.ID,
+		Name:          img.Name,
+		OriginalURL:   img.URL,
+		Status:        img.Status,
+		FailureReason: img.FailureReason,
+		Metadata:      img.Metadata,
+		Variants:      make([]Variant, 0, len(img.Variants)),
+		CreatedAt:     img.CreatedAt,
+		UpdatedAt:     img.UpdatedAt,
+	}
+	for _, variant := range img.Variants {
+		descriptor.Variants = append(descriptor.Variants, Variant{
+			Name:        variant.Preset,
+			Format:      variant.Format,
+			ContentType: variant.ContentType,
+			URL:         variant.URL,
+			Width:       variant.Width,
+			Height:      variant.Height,
+		})
+	}
+	if img.FocalPoint != nil {
+		descriptor.FocalPoint = &FocalPoint{X: img.FocalPoint.X, Y: img.FocalPoint.Y}
+	}
+
+	return json.Marshal(Payload{
+		DeliveryID: delivery.ID,
+		Event:      delivery.Event,
+		CreatedAt:  delivery.CreatedAt,
+		Image:      descriptor,
+	})
+}
@@ -4,9 +4,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/menyasosali/mts/internal/domain"
 	"github.com/menyasosali/mts/internal/service/db"
 	"github.com/menyasosali/mts/internal/service/kafka"
-	"io"
+	"github.com/menyasosali/mts/internal/service/outbox"
 	"net/http"
 
 	"github.com/menyasosali/mts/internal/service/filestorer"
@@ -27,11 +29,9 @@ type ImageResponse struct {
 }
 
 type ImageDescriptorResponse struct {
-	ImageID     string `json:"imageID"`
-	OriginalURL string `json:"originalUrl"`
-	Img512      string `json:"img512"`
-	Img256      string `json:"img256"`
-	Img16       string `json:"img16"`
+	ImageID     string            `json:"imageID"`
+	OriginalURL string            `json:"originalUrl"`
+	Variants    map[string]string `json:"variants"`
 }
 
 func NewTransport(logger logger.Interface, fileStorer filestorer.FileStorerInterface, store db.StoreInterface,
@@ -90,16 +90,9 @@ func (t *Transport) UploadImageHandler(w http.ResponseWriter, r *http.Request) {
 	}
 	defer file.Close()
 
-	imageBytes, err := io.ReadAll(file)
-	if err != nil {
-		t.Logger.Error("Failed to read image bytes", err)
-		http.Error(w, "Failed to read image bytes", http.StatusInternalServerError)
-		return
-	}
-
 	filename := header.Filename
 
-	imgURL, err := t.FileStorer.UploadImage(r.Context(), imageBytes, filename)
+	imgURL, err := t.FileStorer.UploadImage(r.Context(), file, header.Size, filename, header.Header.Get("Content-Type"))
 	if err != nil {
 		t.Logger.Error("Failed to upload image", err)
 		http.Error(w, "Failed to upload image", http.StatusInternalServerError)
@@ -108,7 +101,18 @@ func (t *Transport) UploadImageHandler(w http.ResponseWriter, r *http.Request) {
 
 	t.Logger.Info("117.. - producer.go - FileStorer Upload - success")
 
-	imgID, err := t.Store.UploadImage(r.Context(), filename, imgURL)
+	img := domain.ImgDescriptor{ID: uuid.New().String(), Name: filename, URL: imgURL, ObjectKey: filename}
+
+	message, err := kafka.NewImageJob(img).Marshal()
+	if err != nil {
+		t.Logger.Error("Failed to marshal image job", err)
+		http.Error(w, "Failed to marshal image job", http.StatusInternalServerError)
+		return
+	}
+	t.Logger.Info(fmt.Sprintf("138.. - producer.go - message: %s", message))
+
+	job := outbox.Message{Topic: t.Producer.Cfg.Topic, Key: img.ID, Payload: message}
+	img.ID, err = t.Store.UploadImage(r.Context(), img, job)
 	if err != nil {
 		t.Logger.Error("Failed to save image to db", err)
 		http.Error(w, "Failed to save image to db", http.StatusInternalServerError)
@@ -116,26 +120,11 @@ func (t *Transport) UploadImageHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	response := ImageResponse{
-		ImageID:     imgID,
+		ImageID:     img.ID,
 		Name:        filename,
 		OriginalURL: imgURL,
 	}
 
-	message, err := json.Marshal(response)
-	if err != nil {
-		t.Logger.Error("Failed to marshal response to JSON", err)
-		http.Error(w, "Failed to marshal response to JSON", http.StatusInternalServerError)
-		return
-	}
-	t.Logger.Info(fmt.Sprintf("138.. - producer.go - message: %s", message))
-
-	err = t.Producer.ProduceMessage(r.Context(), message)
-	if err != nil {
-		t.Logger.Error("Failed to produce message to Kafka topic", err)
-		http.Error(w, "Failed to produce message to Kafka topic", http.StatusInternalServerError)
-		return
-	}
-
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
 }
@@ -158,9 +147,10 @@ func (t *Transport) GetImageByIDHandler(w http.ResponseWriter, r *http.Request)
 	response := ImageDescriptorResponse{
 		ImageID:     imageID,
 		OriginalURL: img.URL,
-		Img512:      img.URL512,
-		Img256:      img.URL256,
-		Img16:       img.URL16,
+		Variants:    make(map[string]string, len(img.Variants)),
+	}
+	for _, variant := range img.Variants {
+		response.Variants[variant.Preset+"."+variant.Format] = variant.URL
 	}
 
 	w.Header().Set("Content-Type", "application/json")
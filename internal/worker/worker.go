@@ -4,29 +4,45 @@ import (
 	"context"
 	"fmt"
 	"github.com/menyasosali/mts/config"
+	"github.com/menyasosali/mts/internal/service/db"
 	"github.com/menyasosali/mts/internal/service/filestorer"
 	"github.com/menyasosali/mts/internal/service/kafka"
 	"github.com/menyasosali/mts/internal/service/minio"
 	"github.com/menyasosali/mts/internal/service/resizer"
 	"github.com/menyasosali/mts/pkg/logger"
+	"github.com/menyasosali/mts/pkg/postgres"
 	"log"
 	"os"
 	"os/signal"
-	"time"
 )
 
 func Run(cfg *config.WorkerConfig) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
 	// Logger
 	l := logger.NewLogger(cfg.Log.Level)
 
+	// Postgres
+	pg, err := postgres.New(cfg.Postgres.URL, postgres.MaxPoolSize(cfg.Postgres.PoolMax))
+	if err != nil {
+		l.Fatal(fmt.Errorf("worker - Run - postgres.New: %w", err))
+	}
+	defer pg.Close()
+
+	// DB Store
+	store := db.NewStore(l, pg)
+
 	// Kafka Consumer
 	kafkaConsumerConfig := config.KafkaConfig{
-		Brokers: cfg.Kafka.Brokers,
-		Topic:   cfg.Kafka.Topic,
-		GroupID: cfg.Kafka.GroupID,
+		Brokers:         cfg.Kafka.Brokers,
+		Topic:           cfg.Kafka.Topic,
+		GroupID:         cfg.Kafka.GroupID,
+		DeadLetterTopic: cfg.Kafka.DeadLetterTopic,
+		QuarantineTopic: cfg.Kafka.QuarantineTopic,
+		MaxAttempts:     cfg.Kafka.MaxAttempts,
+		RetryBackoff:    cfg.Kafka.RetryBackoff,
+		MaxRetryBackoff: cfg.Kafka.MaxRetryBackoff,
 	}
 
 	// MinIO
@@ -43,19 +59,43 @@ func Run(cfg *config.WorkerConfig) {
 
 	// File Storer
 	fileStorer := filestorer.NewFileStorer(l, minioClient)
-	l.Info(fmt.Sprintf("46 - fileStorer - worker.go - Run: %s", fileStorer))
 	// Image Resizer
-	processor := resizer.NewResizer(l, fileStorer)
-	l.Info(fmt.Sprintf("49 - processor - worker.go - Run: %s", fileStorer))
+	processor, err := resizer.NewResizer(l, fileStorer, store, store, cfg.Presets)
+	if err != nil {
+		log.Fatal("Failed to create image resizer:", err)
+	}
+
+	// Kafka dead-letter producer
+	deadLetterConfig := config.KafkaConfig{
+		Brokers: cfg.Kafka.Brokers,
+		Topic:   cfg.Kafka.DeadLetterTopic,
+	}
+	deadLetterProducer, err := kafka.NewImageProducer(l, deadLetterConfig)
+	if err != nil {
+		log.Fatal("Failed to create Kafka dead-letter producer:", err)
+	}
+	defer deadLetterProducer.Close()
+
+	// Kafka quarantine producer
+	quarantineConfig := config.KafkaConfig{
+		Brokers: cfg.Kafka.Brokers,
+		Topic:   cfg.Kafka.QuarantineTopic,
+	}
+	quarantineProducer, err := kafka.NewImageProducer(l, quarantineConfig)
+	if err != nil {
+		log.Fatal("Failed to create Kafka quarantine producer:", err)
+	}
+	defer quarantineProducer.Close()
 
 	// Kafka consumer
-	kafkaConsumer, err := kafka.NewImageConsumer(l, processor, kafkaConsumerConfig)
+	kafkaConsumer, err := kafka.NewImageConsumer(l, processor, store, deadLetterProducer, quarantineProducer,
+		kafkaConsumerConfig)
 	if err != nil {
 		log.Fatal("Failed to create Kafka consumer:", err)
 	}
 	defer kafkaConsumer.Close()
 
-	l.Info(fmt.Sprintf("58 - kafkaConsumer Start - worker.go - Run"))
+	l.Info("kafkaConsumer Start - worker.go - Run")
 	kafkaConsumer.Start(ctx)
 
 	// Graceful shutdown
@@ -0,0 +1,6 @@
+ALTER TABLE images
+    DROP CONSTRAINT IF EXISTS images_status_check,
+    DROP COLUMN IF EXISTS status,
+    DROP COLUMN IF EXISTS failure_reason,
+    DROP COLUMN IF EXISTS created_at,
+    DROP COLUMN IF EXISTS updated_at;
@@ -0,0 +1,10 @@
+ALTER TABLE images
+    ADD COLUMN status         VARCHAR(16) NOT NULL DEFAULT 'uploaded',
+    ADD COLUMN failure_reason TEXT        NOT NULL DEFAULT '',
+    ADD COLUMN created_at     TIMESTAMPTZ NOT NULL DEFAULT now(),
+    ADD COLUMN updated_at     TIMESTAMPTZ NOT NULL DEFAULT now(),
+    ADD CONSTRAINT images_status_check
+        CHECK (status IN ('uploaded', 'queued', 'processing', 'ready', 'failed'));
+
+-- baseline писал в url_512 пустую строку, а не NULL, пока worker не обработал изображение
+UPDATE images SET status = 'ready' WHERE COALESCE(url_512, '') <> '';
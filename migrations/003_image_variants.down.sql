@@ -0,0 +1,10 @@
+ALTER TABLE images
+    ADD COLUMN url_512 VARCHAR(255),
+    ADD COLUMN url_256 VARCHAR(255),
+    ADD COLUMN url_16  VARCHAR(255);
+
+UPDATE images i SET url_512 = v.url FROM image_variants v WHERE v.image_id = i.image_id AND v.preset = '512';
+UPDATE images i SET url_256 = v.url FROM image_variants v WHERE v.image_id = i.image_id AND v.preset = '256';
+UPDATE images i SET url_16 = v.url FROM image_variants v WHERE v.image_id = i.image_id AND v.preset = '16';
+
+DROP TABLE IF EXISTS image_variants;
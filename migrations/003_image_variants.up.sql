@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS image_variants(
+    image_id   VARCHAR(36)  NOT NULL REFERENCES images (image_id) ON DELETE CASCADE,
+    preset     VARCHAR(64)  NOT NULL,
+    object_key VARCHAR(255) NOT NULL,
+    url        VARCHAR(255) NOT NULL,
+    width      INTEGER      NOT NULL DEFAULT 0,
+    height     INTEGER      NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ  NOT NULL DEFAULT now(),
+    PRIMARY KEY (image_id, preset)
+);
+
+INSERT INTO image_variants (image_id, preset, object_key, url)
+SELECT image_id, '512', name || '-512', url_512 FROM images WHERE COALESCE(url_512, '') <> ''
+UNION ALL
+SELECT image_id, '256', name || '-256', url_256 FROM images WHERE COALESCE(url_256, '') <> ''
+UNION ALL
+SELECT image_id, '16', name || '-16', url_16 FROM images WHERE COALESCE(url_16, '') <> '';
+
+ALTER TABLE images
+    DROP COLUMN url_512,
+    DROP COLUMN url_256,
+    DROP COLUMN url_16;
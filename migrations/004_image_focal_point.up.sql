@@ -0,0 +1,4 @@
+ALTER TABLE images
+    ADD COLUMN focal_x REAL CHECK (focal_x BETWEEN 0 AND 1),
+    ADD COLUMN focal_y REAL CHECK (focal_y BETWEEN 0 AND 1),
+    ADD CONSTRAINT images_focal_point_check CHECK ((focal_x IS NULL) = (focal_y IS NULL));
@@ -0,0 +1,9 @@
+DELETE FROM image_variants v
+USING image_variants o
+WHERE v.image_id = o.image_id AND v.preset = o.preset AND v.format > o.format;
+
+ALTER TABLE image_variants
+    DROP CONSTRAINT image_variants_pkey,
+    ADD PRIMARY KEY (image_id, preset),
+    DROP COLUMN format,
+    DROP COLUMN content_type;
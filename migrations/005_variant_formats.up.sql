@@ -0,0 +1,9 @@
+ALTER TABLE image_variants
+    ADD COLUMN format       VARCHAR(16) NOT NULL DEFAULT '',
+    ADD COLUMN content_type VARCHAR(64) NOT NULL DEFAULT '';
+
+ALTER TABLE image_variants
+    ALTER COLUMN format DROP DEFAULT,
+    ALTER COLUMN content_type DROP DEFAULT,
+    DROP CONSTRAINT image_variants_pkey,
+    ADD PRIMARY KEY (image_id, preset, format);
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS uploads(
+    upload_id     VARCHAR(36)  PRIMARY KEY,
+    object_key    VARCHAR(255) NOT NULL,
+    multipart_id  TEXT         NOT NULL,
+    filename      VARCHAR(255) NOT NULL,
+    content_type  VARCHAR(255) NOT NULL DEFAULT '',
+    metadata      TEXT         NOT NULL DEFAULT '',
+    upload_length BIGINT       NOT NULL CHECK (upload_length > 0),
+    upload_offset BIGINT       NOT NULL DEFAULT 0,
+    part_count    INTEGER      NOT NULL DEFAULT 0,
+    tail          BYTEA        NOT NULL DEFAULT '',
+    image_id      VARCHAR(36)  REFERENCES images (image_id) ON DELETE SET NULL,
+    created_at    TIMESTAMPTZ  NOT NULL DEFAULT now(),
+    updated_at    TIMESTAMPTZ  NOT NULL DEFAULT now(),
+    CONSTRAINT uploads_offset_check CHECK (upload_offset BETWEEN 0 AND upload_length)
+);
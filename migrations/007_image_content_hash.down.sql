@@ -0,0 +1,8 @@
+ALTER TABLE uploads
+    DROP COLUMN hash_state;
+
+DROP INDEX IF EXISTS images_sha256_idx;
+
+ALTER TABLE images
+    DROP COLUMN object_key,
+    DROP COLUMN sha256;
@@ -0,0 +1,13 @@
+ALTER TABLE images
+    ADD COLUMN object_key VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN sha256     CHAR(64);
+
+-- до адресации по содержимому оригинал лежал в MinIO под именем файла
+UPDATE images SET object_key = name WHERE object_key = '';
+
+ALTER TABLE images ALTER COLUMN object_key DROP DEFAULT;
+
+CREATE UNIQUE INDEX IF NOT EXISTS images_sha256_idx ON images (sha256) WHERE sha256 IS NOT NULL;
+
+ALTER TABLE uploads
+    ADD COLUMN hash_state BYTEA NOT NULL DEFAULT '';
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS outbox(
+    id           BIGSERIAL    PRIMARY KEY,
+    topic        VARCHAR(255) NOT NULL,
+    message_key  VARCHAR(255) NOT NULL DEFAULT '',
+    payload      BYTEA        NOT NULL,
+    attempts     INTEGER      NOT NULL DEFAULT 0,
+    last_error   TEXT         NOT NULL DEFAULT '',
+    created_at   TIMESTAMPTZ  NOT NULL DEFAULT now(),
+    available_at TIMESTAMPTZ  NOT NULL DEFAULT now(),
+    sent_at      TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS outbox_pending_idx ON outbox (available_at, id) WHERE sent_at IS NULL;
+CREATE INDEX IF NOT EXISTS outbox_sent_idx ON outbox (sent_at) WHERE sent_at IS NOT NULL;
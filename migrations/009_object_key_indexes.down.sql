@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS images_status_updated_at_idx;
+DROP INDEX IF EXISTS uploads_object_key_idx;
+DROP INDEX IF EXISTS image_variants_object_key_idx;
+DROP INDEX IF EXISTS images_object_key_idx;
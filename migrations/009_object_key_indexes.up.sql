@@ -0,0 +1,6 @@
+-- поиск ссылок на объекты MinIO при сборке мусора (cmd/gc)
+CREATE INDEX IF NOT EXISTS images_object_key_idx ON images (object_key);
+CREATE INDEX IF NOT EXISTS image_variants_object_key_idx ON image_variants (object_key);
+CREATE INDEX IF NOT EXISTS uploads_object_key_idx ON uploads (object_key);
+CREATE INDEX IF NOT EXISTS images_status_updated_at_idx ON images (updated_at)
+    WHERE status IN ('uploaded', 'queued', 'processing');
@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS images_deleted_updated_at_idx;
+
+DELETE FROM images WHERE status = 'deleted';
+
+ALTER TABLE images
+    DROP CONSTRAINT images_status_check,
+    ADD CONSTRAINT images_status_check
+        CHECK (status IN ('uploaded', 'queued', 'processing', 'ready', 'failed'));
@@ -0,0 +1,6 @@
+ALTER TABLE images
+    DROP CONSTRAINT images_status_check,
+    ADD CONSTRAINT images_status_check
+        CHECK (status IN ('uploaded', 'queued', 'processing', 'ready', 'failed', 'deleted'));
+
+CREATE INDEX IF NOT EXISTS images_deleted_updated_at_idx ON images (updated_at) WHERE status = 'deleted';
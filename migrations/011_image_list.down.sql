@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS images_name_idx;
+DROP INDEX IF EXISTS images_status_created_at_idx;
+DROP INDEX IF EXISTS images_created_at_idx;
@@ -0,0 +1,4 @@
+-- ключи постраничной выборки ListImages; created_at и updated_at добавлены в 002_image_status
+CREATE INDEX IF NOT EXISTS images_created_at_idx ON images (created_at, image_id);
+CREATE INDEX IF NOT EXISTS images_status_created_at_idx ON images (status, created_at, image_id);
+CREATE INDEX IF NOT EXISTS images_name_idx ON images ((name COLLATE "C"), image_id);
@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS images_status_updated ON images;
+DROP TRIGGER IF EXISTS images_status_inserted ON images;
+DROP FUNCTION IF EXISTS notify_image_status();
@@ -0,0 +1,22 @@
+-- смены статуса изображений уходят в канал LISTEN/NOTIFY image_events (db.ImageEventsChannel).
+-- pg_notify падает на payload длиннее 8000 байт и откатил бы смену статуса, поэтому причина ошибки
+-- обрезается, полная остается в images.failure_reason
+CREATE OR REPLACE FUNCTION notify_image_status() RETURNS trigger AS $$
+BEGIN
+    PERFORM pg_notify('image_events', json_build_object(
+        'image_id', NEW.image_id,
+        'status', NEW.status,
+        'failure_reason', left(NEW.failure_reason, 1024)
+    )::text);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER images_status_inserted
+    AFTER INSERT ON images
+    FOR EACH ROW EXECUTE FUNCTION notify_image_status();
+
+CREATE TRIGGER images_status_updated
+    AFTER UPDATE OF status ON images
+    FOR EACH ROW WHEN (OLD.status IS DISTINCT FROM NEW.status)
+    EXECUTE FUNCTION notify_image_status();
@@ -0,0 +1,7 @@
+DROP TRIGGER IF EXISTS images_webhook ON images;
+DROP FUNCTION IF EXISTS enqueue_image_webhook();
+DROP TABLE IF EXISTS webhook_attempts;
+DROP TABLE IF EXISTS webhook_deliveries;
+
+ALTER TABLE images
+    DROP COLUMN callback_url;
@@ -0,0 +1,48 @@
+ALTER TABLE images
+    ADD COLUMN callback_url TEXT NOT NULL DEFAULT '';
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries(
+    delivery_id      BIGSERIAL    PRIMARY KEY,
+    image_id         VARCHAR(36)  NOT NULL REFERENCES images (image_id) ON DELETE CASCADE,
+    url              TEXT         NOT NULL,
+    event            VARCHAR(32)  NOT NULL,
+    -- тело фиксируется при первой попытке, повторы отправляют те же байты
+    payload          BYTEA,
+    status           VARCHAR(16)  NOT NULL DEFAULT 'pending',
+    attempts         INTEGER      NOT NULL DEFAULT 0,
+    last_status_code INTEGER      NOT NULL DEFAULT 0,
+    last_error       TEXT         NOT NULL DEFAULT '',
+    next_attempt_at  TIMESTAMPTZ  NOT NULL DEFAULT now(),
+    created_at       TIMESTAMPTZ  NOT NULL DEFAULT now(),
+    delivered_at     TIMESTAMPTZ,
+    CONSTRAINT webhook_deliveries_status_check CHECK (status IN ('pending', 'delivered', 'failed'))
+);
+
+CREATE INDEX IF NOT EXISTS webhook_deliveries_pending_idx ON webhook_deliveries (next_attempt_at)
+    WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS webhook_deliveries_image_idx ON webhook_deliveries (image_id, delivery_id);
+
+CREATE TABLE IF NOT EXISTS webhook_attempts(
+    delivery_id BIGINT      NOT NULL REFERENCES webhook_deliveries (delivery_id) ON DELETE CASCADE,
+    attempt     INTEGER     NOT NULL,
+    status_code INTEGER     NOT NULL DEFAULT 0,
+    error       TEXT        NOT NULL DEFAULT '',
+    duration_ms INTEGER     NOT NULL DEFAULT 0,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (delivery_id, attempt)
+);
+
+-- завершение обработки (ready или failed) ставит доставку на callback_url изображения
+CREATE OR REPLACE FUNCTION enqueue_image_webhook() RETURNS trigger AS $$
+BEGIN
+    INSERT INTO webhook_deliveries (image_id, url, event)
+    VALUES (NEW.image_id, NEW.callback_url, 'image.' || NEW.status);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER images_webhook
+    AFTER UPDATE OF status ON images
+    FOR EACH ROW
+    WHEN (OLD.status IS DISTINCT FROM NEW.status AND NEW.status IN ('ready', 'failed') AND NEW.callback_url <> '')
+    EXECUTE FUNCTION enqueue_image_webhook();
@@ -0,0 +1,3 @@
+-- размеры, формат, цветовая модель и EXIF оригинала, заполняет worker (domain.ImageMetadata)
+ALTER TABLE images
+    ADD COLUMN metadata JSONB;
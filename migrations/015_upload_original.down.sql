@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS uploads_original_key_idx;
+
+ALTER TABLE uploads
+    DROP COLUMN IF EXISTS original_key;
@@ -0,0 +1,7 @@
+-- ключ оригинала (originals/<sha256>) собранной tus-загрузки: повторный PATCH после сбоя регистрации
+-- не собирает multipart заново, а сразу регистрирует изображение
+ALTER TABLE uploads
+    ADD COLUMN original_key VARCHAR(255) NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS uploads_original_key_idx ON uploads (original_key)
+    WHERE original_key <> '';
@@ -0,0 +1,16 @@
+CREATE OR REPLACE FUNCTION enqueue_image_webhook() RETURNS trigger AS $$
+BEGIN
+    INSERT INTO webhook_deliveries (image_id, url, event)
+    VALUES (NEW.image_id, NEW.callback_url, 'image.' || NEW.status);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS images_webhook ON images;
+CREATE TRIGGER images_webhook
+    AFTER UPDATE OF status ON images
+    FOR EACH ROW
+    WHEN (OLD.status IS DISTINCT FROM NEW.status AND NEW.status IN ('ready', 'failed') AND NEW.callback_url <> '')
+    EXECUTE FUNCTION enqueue_image_webhook();
+
+DROP TABLE IF EXISTS image_callbacks;
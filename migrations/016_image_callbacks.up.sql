@@ -0,0 +1,28 @@
+-- адреса webhook загрузок, совпавших по sha256 с уже существующим изображением: у изображения один
+-- callback_url, повторные загрузки подписываются здесь и получают те же события
+CREATE TABLE IF NOT EXISTS image_callbacks(
+    image_id   VARCHAR(36) NOT NULL REFERENCES images (image_id) ON DELETE CASCADE,
+    url        TEXT        NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (image_id, url)
+);
+
+CREATE OR REPLACE FUNCTION enqueue_image_webhook() RETURNS trigger AS $$
+BEGIN
+    INSERT INTO webhook_deliveries (image_id, url, event)
+    SELECT NEW.image_id, url, 'image.' || NEW.status
+    FROM (
+        SELECT NEW.callback_url AS url WHERE NEW.callback_url <> ''
+        UNION
+        SELECT url FROM image_callbacks WHERE image_id = NEW.image_id
+    ) urls;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS images_webhook ON images;
+CREATE TRIGGER images_webhook
+    AFTER UPDATE OF status ON images
+    FOR EACH ROW
+    WHEN (OLD.status IS DISTINCT FROM NEW.status AND NEW.status IN ('ready', 'failed'))
+    EXECUTE FUNCTION enqueue_image_webhook();
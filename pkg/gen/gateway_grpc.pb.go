@@ -21,8 +21,17 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	Gateway_GetUploadPage_FullMethodName = "/pb.Gateway/GetUploadPage"
-	Gateway_GetImageByID_FullMethodName  = "/pb.Gateway/GetImageByID"
+	Gateway_GetUploadPage_FullMethodName         = "/pb.Gateway/GetUploadPage"
+	Gateway_GetImageByID_FullMethodName          = "/pb.Gateway/GetImageByID"
+	Gateway_ListImages_FullMethodName            = "/pb.Gateway/ListImages"
+	Gateway_BatchGetImages_FullMethodName        = "/pb.Gateway/BatchGetImages"
+	Gateway_ListWebhookDeliveries_FullMethodName = "/pb.Gateway/ListWebhookDeliveries"
+	Gateway_DeleteImage_FullMethodName           = "/pb.Gateway/DeleteImage"
+	Gateway_SetFocalPoint_FullMethodName         = "/pb.Gateway/SetFocalPoint"
+	Gateway_UploadImage_FullMethodName           = "/pb.Gateway/UploadImage"
+	Gateway_WatchImage_FullMethodName            = "/pb.Gateway/WatchImage"
+	Gateway_CreateUpload_FullMethodName          = "/pb.Gateway/CreateUpload"
+	Gateway_CompleteUpload_FullMethodName        = "/pb.Gateway/CompleteUpload"
 )
 
 // GatewayClient is the client API for Gateway service.
@@ -31,6 +40,16 @@ const (
 type GatewayClient interface {
 	GetUploadPage(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*httpbody.HttpBody, error)
 	GetImageByID(ctx context.Context, in *GetImageByIDRequest, opts ...grpc.CallOption) (*GetImageByIDResponse, error)
+	ListImages(ctx context.Context, in *ListImagesRequest, opts ...grpc.CallOption) (*ListImagesResponse, error)
+	BatchGetImages(ctx context.Context, in *BatchGetImagesRequest, opts ...grpc.CallOption) (*BatchGetImagesResponse, error)
+	ListWebhookDeliveries(ctx context.Context, in *ListWebhookDeliveriesRequest, opts ...grpc.CallOption) (*ListWebhookDeliveriesResponse, error)
+	DeleteImage(ctx context.Context, in *DeleteImageRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	SetFocalPoint(ctx context.Context, in *SetFocalPointRequest, opts ...grpc.CallOption) (*GetImageByIDResponse, error)
+	UploadImage(ctx context.Context, opts ...grpc.CallOption) (Gateway_UploadImageClient, error)
+	// по HTTP те же события отдает GET /images/{id}/events (text/event-stream)
+	WatchImage(ctx context.Context, in *WatchImageRequest, opts ...grpc.CallOption) (Gateway_WatchImageClient, error)
+	CreateUpload(ctx context.Context, in *CreateUploadRequest, opts ...grpc.CallOption) (*CreateUploadResponse, error)
+	CompleteUpload(ctx context.Context, in *CompleteUploadRequest, opts ...grpc.CallOption) (*GetImageByIDResponse, error)
 }
 
 type gatewayClient struct {
@@ -59,12 +78,151 @@ func (c *gatewayClient) GetImageByID(ctx context.Context, in *GetImageByIDReques
 	return out, nil
 }
 
+func (c *gatewayClient) ListImages(ctx context.Context, in *ListImagesRequest, opts ...grpc.CallOption) (*ListImagesResponse, error) {
+	out := new(ListImagesResponse)
+	err := c.cc.Invoke(ctx, Gateway_ListImages_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gatewayClient) BatchGetImages(ctx context.Context, in *BatchGetImagesRequest, opts ...grpc.CallOption) (*BatchGetImagesResponse, error) {
+	out := new(BatchGetImagesResponse)
+	err := c.cc.Invoke(ctx, Gateway_BatchGetImages_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gatewayClient) ListWebhookDeliveries(ctx context.Context, in *ListWebhookDeliveriesRequest, opts ...grpc.CallOption) (*ListWebhookDeliveriesResponse, error) {
+	out := new(ListWebhookDeliveriesResponse)
+	err := c.cc.Invoke(ctx, Gateway_ListWebhookDeliveries_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gatewayClient) DeleteImage(ctx context.Context, in *DeleteImageRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Gateway_DeleteImage_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gatewayClient) SetFocalPoint(ctx context.Context, in *SetFocalPointRequest, opts ...grpc.CallOption) (*GetImageByIDResponse, error) {
+	out := new(GetImageByIDResponse)
+	err := c.cc.Invoke(ctx, Gateway_SetFocalPoint_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gatewayClient) UploadImage(ctx context.Context, opts ...grpc.CallOption) (Gateway_UploadImageClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Gateway_ServiceDesc.Streams[0], Gateway_UploadImage_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &gatewayUploadImageClient{stream}
+	return x, nil
+}
+
+type Gateway_UploadImageClient interface {
+	Send(*UploadChunk) error
+	CloseAndRecv() (*UploadImageResponse, error)
+	grpc.ClientStream
+}
+
+type gatewayUploadImageClient struct {
+	grpc.ClientStream
+}
+
+func (x *gatewayUploadImageClient) Send(m *UploadChunk) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *gatewayUploadImageClient) CloseAndRecv() (*UploadImageResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(UploadImageResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *gatewayClient) WatchImage(ctx context.Context, in *WatchImageRequest, opts ...grpc.CallOption) (Gateway_WatchImageClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Gateway_ServiceDesc.Streams[1], Gateway_WatchImage_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &gatewayWatchImageClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Gateway_WatchImageClient interface {
+	Recv() (*ImageEvent, error)
+	grpc.ClientStream
+}
+
+type gatewayWatchImageClient struct {
+	grpc.ClientStream
+}
+
+func (x *gatewayWatchImageClient) Recv() (*ImageEvent, error) {
+	m := new(ImageEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *gatewayClient) CreateUpload(ctx context.Context, in *CreateUploadRequest, opts ...grpc.CallOption) (*CreateUploadResponse, error) {
+	out := new(CreateUploadResponse)
+	err := c.cc.Invoke(ctx, Gateway_CreateUpload_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gatewayClient) CompleteUpload(ctx context.Context, in *CompleteUploadRequest, opts ...grpc.CallOption) (*GetImageByIDResponse, error) {
+	out := new(GetImageByIDResponse)
+	err := c.cc.Invoke(ctx, Gateway_CompleteUpload_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // GatewayServer is the server API for Gateway service.
 // All implementations must embed UnimplementedGatewayServer
 // for forward compatibility
 type GatewayServer interface {
 	GetUploadPage(context.Context, *emptypb.Empty) (*httpbody.HttpBody, error)
 	GetImageByID(context.Context, *GetImageByIDRequest) (*GetImageByIDResponse, error)
+	ListImages(context.Context, *ListImagesRequest) (*ListImagesResponse, error)
+	BatchGetImages(context.Context, *BatchGetImagesRequest) (*BatchGetImagesResponse, error)
+	ListWebhookDeliveries(context.Context, *ListWebhookDeliveriesRequest) (*ListWebhookDeliveriesResponse, error)
+	DeleteImage(context.Context, *DeleteImageRequest) (*emptypb.Empty, error)
+	SetFocalPoint(context.Context, *SetFocalPointRequest) (*GetImageByIDResponse, error)
+	UploadImage(Gateway_UploadImageServer) error
+	// по HTTP те же события отдает GET /images/{id}/events (text/event-stream)
+	WatchImage(*WatchImageRequest, Gateway_WatchImageServer) error
+	CreateUpload(context.Context, *CreateUploadRequest) (*CreateUploadResponse, error)
+	CompleteUpload(context.Context, *CompleteUploadRequest) (*GetImageByIDResponse, error)
 	mustEmbedUnimplementedGatewayServer()
 }
 
@@ -78,6 +236,33 @@ func (UnimplementedGatewayServer) GetUploadPage(context.Context, *emptypb.Empty)
 func (UnimplementedGatewayServer) GetImageByID(context.Context, *GetImageByIDRequest) (*GetImageByIDResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetImageByID not implemented")
 }
+func (UnimplementedGatewayServer) ListImages(context.Context, *ListImagesRequest) (*ListImagesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListImages not implemented")
+}
+func (UnimplementedGatewayServer) BatchGetImages(context.Context, *BatchGetImagesRequest) (*BatchGetImagesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchGetImages not implemented")
+}
+func (UnimplementedGatewayServer) ListWebhookDeliveries(context.Context, *ListWebhookDeliveriesRequest) (*ListWebhookDeliveriesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListWebhookDeliveries not implemented")
+}
+func (UnimplementedGatewayServer) DeleteImage(context.Context, *DeleteImageRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteImage not implemented")
+}
+func (UnimplementedGatewayServer) SetFocalPoint(context.Context, *SetFocalPointRequest) (*GetImageByIDResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetFocalPoint not implemented")
+}
+func (UnimplementedGatewayServer) UploadImage(Gateway_UploadImageServer) error {
+	return status.Errorf(codes.Unimplemented, "method UploadImage not implemented")
+}
+func (UnimplementedGatewayServer) WatchImage(*WatchImageRequest, Gateway_WatchImageServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchImage not implemented")
+}
+func (UnimplementedGatewayServer) CreateUpload(context.Context, *CreateUploadRequest) (*CreateUploadResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateUpload not implemented")
+}
+func (UnimplementedGatewayServer) CompleteUpload(context.Context, *CompleteUploadRequest) (*GetImageByIDResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CompleteUpload not implemented")
+}
 func (UnimplementedGatewayServer) mustEmbedUnimplementedGatewayServer() {}
 
 // UnsafeGatewayServer may be embedded to opt out of forward compatibility for this service.
@@ -127,6 +312,179 @@ func _Gateway_GetImageByID_Handler(srv interface{}, ctx context.Context, dec fun
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Gateway_ListImages_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListImagesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GatewayServer).ListImages(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Gateway_ListImages_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GatewayServer).ListImages(ctx, req.(*ListImagesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gateway_BatchGetImages_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchGetImagesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GatewayServer).BatchGetImages(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Gateway_BatchGetImages_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GatewayServer).BatchGetImages(ctx, req.(*BatchGetImagesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gateway_ListWebhookDeliveries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListWebhookDeliveriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GatewayServer).ListWebhookDeliveries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Gateway_ListWebhookDeliveries_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GatewayServer).ListWebhookDeliveries(ctx, req.(*ListWebhookDeliveriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gateway_DeleteImage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteImageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GatewayServer).DeleteImage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Gateway_DeleteImage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GatewayServer).DeleteImage(ctx, req.(*DeleteImageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gateway_SetFocalPoint_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetFocalPointRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GatewayServer).SetFocalPoint(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Gateway_SetFocalPoint_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GatewayServer).SetFocalPoint(ctx, req.(*SetFocalPointRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gateway_UploadImage_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(GatewayServer).UploadImage(&gatewayUploadImageServer{stream})
+}
+
+type Gateway_UploadImageServer interface {
+	SendAndClose(*UploadImageResponse) error
+	Recv() (*UploadChunk, error)
+	grpc.ServerStream
+}
+
+type gatewayUploadImageServer struct {
+	grpc.ServerStream
+}
+
+func (x *gatewayUploadImageServer) SendAndClose(m *UploadImageResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *gatewayUploadImageServer) Recv() (*UploadChunk, error) {
+	m := new(UploadChunk)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _Gateway_WatchImage_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchImageRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(GatewayServer).WatchImage(m, &gatewayWatchImageServer{stream})
+}
+
+type Gateway_WatchImageServer interface {
+	Send(*ImageEvent) error
+	grpc.ServerStream
+}
+
+type gatewayWatchImageServer struct {
+	grpc.ServerStream
+}
+
+func (x *gatewayWatchImageServer) Send(m *ImageEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Gateway_CreateUpload_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateUploadRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GatewayServer).CreateUpload(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Gateway_CreateUpload_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GatewayServer).CreateUpload(ctx, req.(*CreateUploadRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gateway_CompleteUpload_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CompleteUploadRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GatewayServer).CompleteUpload(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Gateway_CompleteUpload_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GatewayServer).CompleteUpload(ctx, req.(*CompleteUploadRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Gateway_ServiceDesc is the grpc.ServiceDesc for Gateway service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -142,7 +500,46 @@ var Gateway_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetImageByID",
 			Handler:    _Gateway_GetImageByID_Handler,
 		},
+		{
+			MethodName: "ListImages",
+			Handler:    _Gateway_ListImages_Handler,
+		},
+		{
+			MethodName: "BatchGetImages",
+			Handler:    _Gateway_BatchGetImages_Handler,
+		},
+		{
+			MethodName: "ListWebhookDeliveries",
+			Handler:    _Gateway_ListWebhookDeliveries_Handler,
+		},
+		{
+			MethodName: "DeleteImage",
+			Handler:    _Gateway_DeleteImage_Handler,
+		},
+		{
+			MethodName: "SetFocalPoint",
+			Handler:    _Gateway_SetFocalPoint_Handler,
+		},
+		{
+			MethodName: "CreateUpload",
+			Handler:    _Gateway_CreateUpload_Handler,
+		},
+		{
+			MethodName: "CompleteUpload",
+			Handler:    _Gateway_CompleteUpload_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "UploadImage",
+			Handler:       _Gateway_UploadImage_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "WatchImage",
+			Handler:       _Gateway_WatchImage_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/gateway.proto",
 }